@@ -1,43 +1,145 @@
 package author
 
 import (
+	"database/sql"
+	"strings"
+
 	"developer.zopsmart.com/go/gofr/pkg/gofr"
+	"mytest/datastore"
 	"mytest/models"
+	"mytest/models/errors"
 )
 
 type Datastore struct {
+	// tx is the transaction the statements run in, the DB of the context when nil
+	tx *sql.Tx
 }
 
 func New() Datastore {
 	return Datastore{}
 }
 
+// NewTx is to build the author Datastore running its statements in the given transaction
+func NewTx(tx *sql.Tx) Datastore {
+	return Datastore{tx: tx}
+}
+
+// db is to get where the statements run, the transaction of the datastore or else the DB of the context
+func (d Datastore) db(c *gofr.Context) datastore.Executor {
+	if d.tx != nil {
+		return d.tx
+	}
+
+	return c.DB()
+}
+
+// conflict is to map a clash with the pen name of another author to a Conflict
+func conflict(err error) error {
+	return datastore.Conflict(err, errors.CodePenNameTaken, "pen name is taken by another author")
+}
+
 // Post method is to post the data in Author table
 func (d Datastore) Post(c *gofr.Context, auth models.Author) (models.Author, error) {
-	// inserting data into db
-	_, err := c.DB().Exec("insert into Author(authorId,firstName,lastName,dob,penName) values (?,?,?,?,?)",
-		auth.AuthID, auth.FirstName, auth.LastName, auth.Dob, auth.PenName)
+	// inserting data into db, authorId is generated by AUTO_INCREMENT
+	res, err := d.db(c).Exec("insert into Author(firstName,lastName,dob,penName) values (?,?,?,?)",
+		auth.FirstName, auth.LastName, auth.Dob, auth.PenName)
+	if err != nil {
+		return models.Author{}, conflict(err)
+	}
+
+	id, err := res.LastInsertId()
 	if err != nil {
 		return models.Author{}, err
 	}
 
+	auth.AuthID = int(id)
+	// a new author is at the first version, the default of the column
+	auth.Version = 1
+
 	return auth, nil
 }
 
-// Update method is to update the data in Author table
-func (d Datastore) Update(c *gofr.Context, id int, auth models.Author) (models.Author, error) {
-	_, err := c.DB().Exec("UPDATE Author SET firstName=?, lastName=? , dob=? , penName=? WHERE authorId=?",
-		auth.FirstName, auth.LastName, auth.Dob, auth.PenName, id)
+// Update method is to update the data in Author table and read it back. When a version is given the author
+// is changed only if it is still at that version, sql.ErrNoRows is returned otherwise.
+func (d Datastore) Update(c *gofr.Context, id int, auth models.Author, version int) (models.Author, error) {
+	res, err := d.db(c).Exec("UPDATE Author SET firstName=?, lastName=? , dob=? , penName=?, version=version+1 "+
+		"WHERE authorId=?"+datastore.VersionClause(version),
+		datastore.VersionArgs([]interface{}{auth.FirstName, auth.LastName, auth.Dob, auth.PenName, id}, version)...)
 	if err != nil {
+		return models.Author{}, conflict(err)
+	}
+
+	if err := datastore.CheckVersion(res, version); err != nil {
 		return models.Author{}, err
 	}
 
-	return auth, nil
+	// reading back the stored author
+	return d.IncludeAuthor(c, id)
+}
+
+// Patch method is to change only the given fields of an author and read it back, a given version is honoured
+// as in Update
+func (d Datastore) Patch(c *gofr.Context, id int, patch models.AuthorPatch, version int) (models.Author, error) {
+	var (
+		set  []string
+		args []interface{}
+	)
+
+	if patch.FirstName != nil {
+		set = append(set, "firstName=?")
+		args = append(args, *patch.FirstName)
+	}
+
+	if patch.LastName != nil {
+		set = append(set, "lastName=?")
+		args = append(args, *patch.LastName)
+	}
+
+	if patch.Dob != nil {
+		set = append(set, "dob=?")
+		args = append(args, *patch.Dob)
+	}
+
+	if patch.PenName != nil {
+		set = append(set, "penName=?")
+		args = append(args, *patch.PenName)
+	}
+
+	if len(set) == 0 {
+		return d.getVersion(c, id, version)
+	}
+
+	res, err := d.db(c).Exec("UPDATE Author SET "+strings.Join(set, ",")+", version=version+1 WHERE authorId=?"+
+		datastore.VersionClause(version), datastore.VersionArgs(append(args, id), version)...)
+	if err != nil {
+		return models.Author{}, conflict(err)
+	}
+
+	if err := datastore.CheckVersion(res, version); err != nil {
+		return models.Author{}, err
+	}
+
+	return d.IncludeAuthor(c, id)
 }
 
-// Delete method is to delete the data in Author
-func (d Datastore) Delete(c *gofr.Context, id int) (int, error) {
-	res, err := c.DB().Exec("delete from Author where authorId=?", id)
+// getVersion is to read the author back as it is, sql.ErrNoRows is returned when it is not at the given version
+func (d Datastore) getVersion(c *gofr.Context, id, version int) (models.Author, error) {
+	author, err := d.IncludeAuthor(c, id)
+	if err != nil {
+		return models.Author{}, err
+	}
+
+	if version > 0 && author.Version != version {
+		return models.Author{}, sql.ErrNoRows
+	}
+
+	return author, nil
+}
+
+// Delete method is to delete the data in Author, when a version is given only if the author is still at it
+func (d Datastore) Delete(c *gofr.Context, id, version int) (int, error) {
+	res, err := d.db(c).Exec("delete from Author where authorId=?"+datastore.VersionClause(version),
+		datastore.VersionArgs([]interface{}{id}, version)...)
 	if err != nil {
 		return 0, err
 	}
@@ -50,28 +152,136 @@ func (d Datastore) Delete(c *gofr.Context, id int) (int, error) {
 	return int(rowAffected), nil
 }
 
+// GetAll method is to get all the authors from Author table
+func (d Datastore) GetAll(c *gofr.Context) ([]models.Author, error) {
+	rows, err := d.db(c).Query("select " + authorColumns + " from Author")
+	if err != nil {
+		return nil, err
+	}
+
+	// Closing rows
+	defer rows.Close()
+
+	var authors []models.Author
+
+	// Iterate to all authors
+	for rows.Next() {
+		author, err := scanAuthor(rows)
+		if err != nil {
+			return []models.Author{}, err
+		}
+
+		authors = append(authors, author)
+	}
+
+	return authors, nil
+}
+
+// GetByID method is to get the author by its ID
+func (d Datastore) GetByID(c *gofr.Context, id int) (models.Author, error) {
+	return d.IncludeAuthor(c, id)
+}
+
 // IncludeAuthor details by its ID
 func (d Datastore) IncludeAuthor(c *gofr.Context, id int) (models.Author, error) {
-	row := c.DB().QueryRow("select * from Author where authorId=?", id)
+	row := d.db(c).QueryRow("select "+authorColumns+" from Author where authorId=?", id)
 
-	var author models.Author
+	return scanAuthor(row)
+}
 
-	if err := row.Scan(&author.AuthID, &author.FirstName, &author.LastName, &author.Dob, &author.PenName); err != nil {
-		return models.Author{}, err
+// GetByPenName method is to get the author by its pen name whatever the case, sql.ErrNoRows is returned
+// when no author has it
+func (d Datastore) GetByPenName(c *gofr.Context, penName string) (models.Author, error) {
+	row := d.db(c).QueryRow("select "+authorColumns+" from Author where LOWER(penName)=LOWER(?)", penName)
+
+	return scanAuthor(row)
+}
+
+// Lock method is to get the author holding a shared lock on its row, so that it is neither deleted nor
+// changed until the transaction of the datastore ends, sql.ErrNoRows is returned when there is none
+func (d Datastore) Lock(c *gofr.Context, id int) (models.Author, error) {
+	row := d.db(c).QueryRow("select "+authorColumns+" from Author where authorId=? FOR SHARE", id)
+
+	return scanAuthor(row)
+}
+
+// LockForUpdate method is to get the author holding an exclusive lock on its row, so that it is neither
+// locked nor changed by another transaction until the transaction of the datastore ends, sql.ErrNoRows is
+// returned when there is none
+func (d Datastore) LockForUpdate(c *gofr.Context, id int) (models.Author, error) {
+	row := d.db(c).QueryRow("select "+authorColumns+" from Author where authorId=? FOR UPDATE", id)
+
+	return scanAuthor(row)
+}
+
+// IncludeAuthors method is to get the details of all the given authors in a single query, keyed by authorId
+func (d Datastore) IncludeAuthors(c *gofr.Context, ids []int) (map[int]models.Author, error) {
+	authors := make(map[int]models.Author, len(ids))
+
+	if len(ids) == 0 {
+		return authors, nil
 	}
 
-	return author, nil
+	args := make([]interface{}, len(ids))
+	for i := range ids {
+		args[i] = ids[i]
+	}
+
+	placeholders := strings.TrimSuffix(strings.Repeat("?,", len(ids)), ",")
+
+	rows, err := d.db(c).Query("select "+authorColumns+" from Author where authorId in ("+placeholders+")", args...)
+	if err != nil {
+		return nil, err
+	}
+
+	// Closing rows
+	defer rows.Close()
+
+	for rows.Next() {
+		author, err := scanAuthor(rows)
+		if err != nil {
+			return nil, err
+		}
+
+		authors[author.AuthID] = author
+	}
+
+	return authors, nil
 }
 
-// IsAuthorIDPresent method is to check weather author is present in DB or not
-func (d Datastore) IsAuthorIDPresent(c *gofr.Context, id int) bool {
-	var author models.Author
+// Exists method is to check whether the author is stored, an error is returned only when the DB fails
+func (d Datastore) Exists(c *gofr.Context, id int) (bool, error) {
+	var one int
 
-	row := c.DB().QueryRow("select * from Author where authorId=?", id)
+	err := d.db(c).QueryRow("SELECT 1 FROM Author WHERE authorId=? LIMIT 1", id).Scan(&one)
 
-	if err := row.Scan(&author.AuthID, &author.FirstName, &author.LastName, &author.Dob, &author.PenName); err != nil {
-		return true
+	switch {
+	case err == sql.ErrNoRows:
+		return false, nil
+	case err != nil:
+		return false, err
 	}
 
-	return false
+	return true, nil
+}
+
+// authorFields is the mapping of the Author columns to the fields of author
+func authorFields(author *models.Author) []datastore.Field {
+	return []datastore.Field{{Column: "authorId", Dest: &author.AuthID}, {Column: "firstName", Dest: &author.FirstName},
+		{Column: "lastName", Dest: &author.LastName}, {Column: "dob", Dest: &author.Dob},
+		{Column: "penName", Dest: &author.PenName}, {Column: "version", Dest: &author.Version}}
+}
+
+// authorColumns is the column list every read of an author selects
+var authorColumns = datastore.Columns(authorFields(&models.Author{}))
+
+// scanAuthor is to scan a single Author row selected with authorColumns
+func scanAuthor(row datastore.Row) (models.Author, error) {
+	var author models.Author
+
+	if err := datastore.Scan(row, authorFields(&author)); err != nil {
+		return models.Author{}, err
+	}
+
+	return author, nil
 }
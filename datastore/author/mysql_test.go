@@ -1,6 +1,7 @@
 package author
 
 import (
+	"database/sql"
 	"database/sql/driver"
 	"developer.zopsmart.com/go/gofr/pkg/gofr"
 	"errors"
@@ -9,25 +10,37 @@ import (
 	"testing"
 
 	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/go-sql-driver/mysql"
 
 	"mytest/models"
+	modelErrors "mytest/models/errors"
 )
 
 // Testing Post Author
 func TestAuthor_Post(t *testing.T) {
 	testcases := []struct {
-		desc         string
-		req          models.Author
-		resp         models.Author
-		lastInsertID int64
-		rowAffected  int64
-		err          error
+		desc    string
+		req     models.Author
+		resp    models.Author
+		res     driver.Result
+		execErr error
+		err     error
 	}{
-		{desc: "valid details", req: models.Author{AuthID: 1, FirstName: "Chetan", LastName: "Bhagat", Dob: "06/04/2001",
-			PenName: "Chetan"}, resp: models.Author{AuthID: 1, FirstName: "Chetan", LastName: "Bhagat", Dob: "06/04/2001",
-			PenName: "Chetan"}, lastInsertID: 1, rowAffected: 1},
-		{desc: "duplicate id", req: models.Author{AuthID: 3, FirstName: "Chetan", LastName: "Bhagat", Dob: "06/04/2001",
-			PenName: "Chetan"}, lastInsertID: 0, rowAffected: 0, err: errors.New("error")},
+		{desc: "valid details", req: models.Author{FirstName: "Chetan", LastName: "Bhagat",
+			Dob: models.NewDate(2001, 4, 6), PenName: "Chetan"}, resp: models.Author{AuthID: 1, FirstName: "Chetan",
+			LastName: "Bhagat", Dob: models.NewDate(2001, 4, 6), PenName: "Chetan", Version: 1},
+			res: sqlmock.NewResult(1, 1)},
+		{desc: "error in insert", req: models.Author{FirstName: "Chetan", LastName: "Bhagat",
+			Dob: models.NewDate(2001, 4, 6), PenName: "Chetan"}, res: sqlmock.NewResult(0, 0), execErr: errors.New("error"),
+			err: errors.New("error")},
+		{desc: "error in lastInsertId", req: models.Author{FirstName: "Chetan", LastName: "Bhagat",
+			Dob: models.NewDate(2001, 4, 6), PenName: "Chetan"}, res: sqlmock.NewErrorResult(errors.New("error")),
+			err: errors.New("error")},
+		{desc: "pen name taken", req: models.Author{FirstName: "Chetan", LastName: "Bhagat",
+			Dob: models.NewDate(2001, 4, 6), PenName: "Chetan"}, res: sqlmock.NewResult(0, 0),
+			err: modelErrors.Conflict{Code: modelErrors.CodePenNameTaken,
+				Reason: "pen name is taken by another author"},
+			execErr: &mysql.MySQLError{Number: 1062, Message: "Duplicate entry for key 'Author.uq_author_pen_name'"}},
 	}
 
 	// Customize SQL query matching
@@ -41,9 +54,9 @@ func TestAuthor_Post(t *testing.T) {
 
 	for i, v := range testcases {
 		// mocking insert exec query
-		mock.ExpectExec("insert into Author(authorId,firstName,lastName,dob,penName) values (?,?,?,?,?)").
-			WithArgs(v.req.AuthID, v.req.FirstName, v.req.LastName, v.req.Dob, v.req.PenName).
-			WillReturnResult(sqlmock.NewResult(v.lastInsertID, v.rowAffected)).WillReturnError(v.err)
+		mock.ExpectExec("insert into Author(firstName,lastName,dob,penName) values (?,?,?,?)").
+			WithArgs(v.req.FirstName, v.req.LastName, v.req.Dob, v.req.PenName).
+			WillReturnResult(v.res).WillReturnError(v.execErr)
 
 		app := gofr.New()
 		app.DB().DB = db
@@ -60,23 +73,250 @@ func TestAuthor_Post(t *testing.T) {
 		}
 
 		// Comparing errors
+		if !reflect.DeepEqual(err, v.err) {
+			t.Errorf("desc : %v ,[TEST%d]Failed. Got %v\tExpected %v\n", v.desc, i+1, err, v.err)
+		}
+	}
+}
+
+// TestAuthor_GetAll Testing get all authors
+func TestAuthor_GetAll(t *testing.T) {
+	testcases := []struct {
+		desc string
+		resp []models.Author
+		rows *sqlmock.Rows
+		err  error
+	}{
+		{desc: "valid details", resp: []models.Author{
+			{AuthID: 1, FirstName: "Chetan", LastName: "Bhagat", Dob: models.NewDate(2001, 4, 6), PenName: "Chetan",
+				Version: 1},
+			{AuthID: 2, FirstName: "Rajan", LastName: "Sharma", Dob: models.NewDate(2001, 4, 26), PenName: "Rajan",
+				Version: 1}},
+			rows: sqlmock.NewRows([]string{"authorId", "fistName", "lastName", "dob", "penName", "version"}).
+				AddRow(1, "Chetan", "Bhagat", "2001-04-06", "Chetan", 1).
+				AddRow(2, "Rajan", "Sharma", "2001-04-26", "Rajan", 1)},
+		{desc: "error in scanning", resp: []models.Author{}, rows: sqlmock.NewRows([]string{"authorId", "fistName",
+			"lastName", "dob", "penName", "version"}).AddRow("abc", "Chetan", "Bhagat", "2001-04-06", "Chetan", 1)},
+		{desc: "error in select all", rows: sqlmock.NewRows([]string{}), err: errors.New("error in select all")},
+	}
+
+	// Customize SQL query matching
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	if err != nil {
+		log.Printf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+
+	app := gofr.New()
+	app.DB().DB = db
+
+	ctx := gofr.NewContext(nil, nil, app)
+
+	// Closing DB after all things done
+	defer db.Close()
+
+	for i, v := range testcases {
+		// Mocking select all authors query
+		mock.ExpectQuery("select authorId, firstName, lastName, dob, penName, version from Author").WillReturnRows(v.rows).WillReturnError(v.err)
+
+		datastore := New()
+
+		resp, err := datastore.GetAll(ctx)
+
+		if !reflect.DeepEqual(resp, v.resp) {
+			t.Errorf("desc : %v ,[TEST%d]Failed. Got %v\tExpected %v\n", v.desc, i+1, resp, v.resp)
+		}
+
 		if err != nil {
 			log.Printf("desc : %v ,[TEST%d]Failed. Got %v", v.desc, i+1, err)
 		}
 	}
 }
 
-// Testing Put Author
-func TestAuthor_Put(t *testing.T) {
+// TestAuthor_GetByID Testing get author by id
+func TestAuthor_GetByID(t *testing.T) {
 	testcases := []struct {
 		desc string
 		id   int
 		resp models.Author
-		res  driver.Result
+		rows *sqlmock.Rows
 		err  error
+	}{
+		{desc: "valid", id: 1, resp: models.Author{AuthID: 1, FirstName: "Chetan", LastName: "Bhagat",
+			Dob: models.NewDate(2001, 4, 6), PenName: "Chetan", Version: 1},
+			rows: sqlmock.NewRows([]string{"authorId", "fistName", "lastName", "dob", "penName", "version"}).
+				AddRow(1, "Chetan", "Bhagat", "2001-04-06", "Chetan", 1)},
+		{desc: "id not exist", id: 11, resp: models.Author{}, rows: sqlmock.NewRows([]string{"authorId", "fistName", "lastName", "dob", "penName", "version"})},
+	}
+
+	// Customize SQL query matching
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	if err != nil {
+		log.Printf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+
+	app := gofr.New()
+	app.DB().DB = db
+
+	ctx := gofr.NewContext(nil, nil, app)
+
+	// Closing DB after all things done
+	defer db.Close()
+
+	for i, v := range testcases {
+		// Mocking select author query
+		mock.ExpectQuery("select authorId, firstName, lastName, dob, penName, version from Author where authorId=?").WithArgs(v.id).
+			WillReturnRows(v.rows).WillReturnError(v.err)
+
+		datastore := New()
+
+		resp, err := datastore.GetByID(ctx, v.id)
+
+		if !reflect.DeepEqual(resp, v.resp) {
+			t.Errorf("desc : %v ,[TEST%d]Failed. Got %v\tExpected %v\n", v.desc, i+1, resp, v.resp)
+		}
+
+		if err != nil {
+			log.Printf("desc : %v ,[TEST%d]Failed. Got %v", v.desc, i+1, err)
+		}
+	}
+}
+
+// TestAuthor_GetByPenName Testing get author by its pen name whatever the case
+func TestAuthor_GetByPenName(t *testing.T) {
+	testcases := []struct {
+		desc    string
+		penName string
+		resp    models.Author
+		rows    *sqlmock.Rows
+		err     error
+	}{
+		{desc: "valid", penName: "CHETAN", resp: models.Author{AuthID: 1, FirstName: "Chetan", LastName: "Bhagat",
+			Dob: models.NewDate(2001, 4, 6), PenName: "Chetan", Version: 1},
+			rows: sqlmock.NewRows([]string{"authorId", "fistName", "lastName", "dob", "penName", "version"}).
+				AddRow(1, "Chetan", "Bhagat", "2001-04-06", "Chetan", 1)},
+		{desc: "pen name not exist", penName: "Ruskin",
+			rows: sqlmock.NewRows([]string{"authorId", "fistName", "lastName", "dob", "penName", "version"}), err: sql.ErrNoRows},
+	}
+
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	if err != nil {
+		log.Printf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+
+	app := gofr.New()
+	app.DB().DB = db
+
+	ctx := gofr.NewContext(nil, nil, app)
+
+	defer db.Close()
+
+	for i, v := range testcases {
+		mock.ExpectQuery("select authorId, firstName, lastName, dob, penName, version from Author where LOWER(penName)=LOWER(?)").WithArgs(v.penName).
+			WillReturnRows(v.rows)
+
+		datastore := New()
+
+		resp, err := datastore.GetByPenName(ctx, v.penName)
+
+		if !reflect.DeepEqual(resp, v.resp) {
+			t.Errorf("desc : %v ,[TEST%d]Failed. Got %v\tExpected %v\n", v.desc, i+1, resp, v.resp)
+		}
+
+		if !reflect.DeepEqual(err, v.err) {
+			t.Errorf("desc : %v ,[TEST%d]Failed. Got %v\tExpected %v\n", v.desc, i+1, err, v.err)
+		}
+	}
+}
+
+// TestAuthor_Lock Testing get author holding a shared or an exclusive lock on it in the transaction of the datastore
+func TestAuthor_Lock(t *testing.T) {
+	testcases := []struct {
+		desc      string
+		id        int
+		forUpdate bool
+		resp      models.Author
+		rows      *sqlmock.Rows
+		err       error
+	}{
+		{desc: "valid", id: 1, resp: models.Author{AuthID: 1, FirstName: "Chetan", LastName: "Bhagat",
+			Dob: models.NewDate(2001, 4, 6), PenName: "Chetan", Version: 1},
+			rows: sqlmock.NewRows([]string{"authorId", "fistName", "lastName", "dob", "penName", "version"}).
+				AddRow(1, "Chetan", "Bhagat", "2001-04-06", "Chetan", 1)},
+		{desc: "id not exist", id: 10,
+			rows: sqlmock.NewRows([]string{"authorId", "fistName", "lastName", "dob", "penName", "version"}), err: sql.ErrNoRows},
+		{desc: "for update", id: 2, forUpdate: true, resp: models.Author{AuthID: 2, FirstName: "Chetan",
+			LastName: "Bhagat", Dob: models.NewDate(2001, 4, 6), PenName: "Chetan", Version: 3},
+			rows: sqlmock.NewRows([]string{"authorId", "fistName", "lastName", "dob", "penName", "version"}).
+				AddRow(2, "Chetan", "Bhagat", "2001-04-06", "Chetan", 3)},
+		{desc: "for update id not exist", id: 11, forUpdate: true,
+			rows: sqlmock.NewRows([]string{"authorId", "fistName", "lastName", "dob", "penName", "version"}), err: sql.ErrNoRows},
+	}
+
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	if err != nil {
+		log.Printf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+
+	app := gofr.New()
+	app.DB().DB = db
+
+	ctx := gofr.NewContext(nil, nil, app)
+
+	defer db.Close()
+
+	for i, v := range testcases {
+		query := "select authorId, firstName, lastName, dob, penName, version from Author where authorId=? FOR SHARE"
+		if v.forUpdate {
+			query = "select authorId, firstName, lastName, dob, penName, version from Author where authorId=? FOR UPDATE"
+		}
+
+		mock.ExpectBegin()
+		mock.ExpectQuery(query).WithArgs(v.id).WillReturnRows(v.rows)
+
+		tx, err := db.Begin()
+		if err != nil {
+			t.Fatalf("desc : %v ,[TEST%d]Failed. Got %v\n", v.desc, i+1, err)
+		}
+
+		var resp models.Author
+
+		if v.forUpdate {
+			resp, err = NewTx(tx).LockForUpdate(ctx, v.id)
+		} else {
+			resp, err = NewTx(tx).Lock(ctx, v.id)
+		}
+
+		if !reflect.DeepEqual(resp, v.resp) {
+			t.Errorf("desc : %v ,[TEST%d]Failed. Got %v\tExpected %v\n", v.desc, i+1, resp, v.resp)
+		}
+
+		if !reflect.DeepEqual(err, v.err) {
+			t.Errorf("desc : %v ,[TEST%d]Failed. Got %v\tExpected %v\n", v.desc, i+1, err, v.err)
+		}
+
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Errorf("desc : %v ,[TEST%d]Failed. Got %v\n", v.desc, i+1, err)
+		}
+	}
+}
+
+// Testing Put Author
+func TestAuthor_Put(t *testing.T) {
+	testcases := []struct {
+		desc    string
+		id      int
+		version int
+		resp    models.Author
+		res     driver.Result
+		err     error
 	}{
 		{desc: "valid", id: 1, resp: models.Author{AuthID: 1, FirstName: "Rajan",
-			LastName: "Sharma", Dob: "26/04/2001", PenName: "Rajan"}, res: sqlmock.NewResult(1, 1)},
+			LastName: "Sharma", Dob: models.NewDate(2001, 4, 26), PenName: "Rajan", Version: 2}, res: sqlmock.NewResult(1, 1)},
+		{desc: "stored author", id: 2, resp: models.Author{AuthID: 2, FirstName: "Chetan",
+			LastName: "Bhagat", Dob: models.NewDate(2001, 4, 6), PenName: "Chetan", Version: 2}, res: sqlmock.NewResult(0, 1)},
+		{desc: "at the version", id: 3, version: 4, resp: models.Author{AuthID: 3, FirstName: "Rajan",
+			LastName: "Sharma", Dob: models.NewDate(2001, 4, 26), PenName: "Rajan", Version: 5}, res: sqlmock.NewResult(0, 1)},
+		{desc: "version moved on", id: 3, version: 4, res: sqlmock.NewResult(0, 0), err: sql.ErrNoRows},
 		{desc: "id not exist", id: 11, res: sqlmock.NewResult(0, 0), err: errors.New("error")},
 	}
 
@@ -95,22 +335,133 @@ func TestAuthor_Put(t *testing.T) {
 	defer db.Close()
 
 	for i, v := range testcases {
-		// Mocking Exec for updating data
-		mock.ExpectExec("UPDATE Author SET firstName=?, lastName=? , dob=? , penName=? WHERE authorId=?").
-			WithArgs(v.resp.FirstName, v.resp.LastName, v.resp.Dob, v.resp.PenName, v.id).WillReturnResult(v.res).WillReturnError(v.err)
+		query := "UPDATE Author SET firstName=?, lastName=? , dob=? , penName=?, version=version+1 WHERE authorId=?"
+		args := []driver.Value{v.resp.FirstName, v.resp.LastName, v.resp.Dob, v.resp.PenName, v.id}
+
+		if v.version > 0 {
+			query += " AND version=?"
+			args = append(args, v.version)
+		}
+
+		// Mocking Exec for updating data, an error of the DB and a moved on version are told apart
+		exec := mock.ExpectExec(query).WithArgs(args...).WillReturnResult(v.res)
+		if v.err != sql.ErrNoRows {
+			exec.WillReturnError(v.err)
+		}
+
+		// Mocking Query for reading back the stored author
+		if v.err == nil {
+			mock.ExpectQuery("select authorId, firstName, lastName, dob, penName, version from Author where authorId=?").
+				WithArgs(v.id).WillReturnRows(sqlmock.NewRows([]string{"authorId", "firstName", "lastName", "dob",
+				"penName", "version"}).AddRow(v.resp.AuthID, v.resp.FirstName, v.resp.LastName, v.resp.Dob,
+				v.resp.PenName, v.resp.Version))
+		}
 
 		datastore := New()
 
-		resp, err := datastore.Update(ctx, v.id, v.resp)
+		resp, err := datastore.Update(ctx, v.id, v.resp, v.version)
 
 		// Comparing body
 		if !reflect.DeepEqual(resp, v.resp) {
 			t.Errorf("desc : %v ,[TEST%d]Failed. Got %v\tExpected %v\n", v.desc, i+1, resp, v.resp)
 		}
 
-		// Getting error
-		if err != nil {
-			log.Printf("desc : %v ,[TEST%d]Failed. Got %v", v.desc, i+1, err)
+		if !reflect.DeepEqual(err, v.err) {
+			t.Errorf("desc : %v ,[TEST%d]Failed. Got %v\tExpected %v\n", v.desc, i+1, err, v.err)
+		}
+	}
+}
+
+// Testing Patch Author
+func TestAuthor_Patch(t *testing.T) {
+	firstName, penName := "Rajan", "Raj"
+	cols := []string{"authorId", "firstName", "lastName", "dob", "penName", "version"}
+
+	testcases := []struct {
+		desc    string
+		id      int
+		version int
+		patch   models.AuthorPatch
+		query   string
+		args    []driver.Value
+		// moved is whether the stored author is at another version than the given one
+		moved bool
+		rows  *sqlmock.Rows
+		resp  models.Author
+		err   error
+	}{
+		{desc: "first name only", id: 1, patch: models.AuthorPatch{FirstName: &firstName},
+			query: "UPDATE Author SET firstName=?, version=version+1 WHERE authorId=?", args: []driver.Value{firstName, 1},
+			rows: sqlmock.NewRows(cols).AddRow(1, firstName, "Bhagat", "2001-04-06", "Chetan", 1),
+			resp: models.Author{AuthID: 1, FirstName: firstName, LastName: "Bhagat", Dob: models.NewDate(2001, 4, 6),
+				PenName: "Chetan", Version: 1}},
+		{desc: "first name and pen name", id: 2, patch: models.AuthorPatch{FirstName: &firstName, PenName: &penName},
+			query: "UPDATE Author SET firstName=?,penName=?, version=version+1 WHERE authorId=?",
+			args:  []driver.Value{firstName, penName, 2},
+			rows:  sqlmock.NewRows(cols).AddRow(2, firstName, "Bhagat", "2001-04-06", penName, 1),
+			resp: models.Author{AuthID: 2, FirstName: firstName, LastName: "Bhagat", Dob: models.NewDate(2001, 4, 6),
+				PenName: penName, Version: 1}},
+		{desc: "empty patch", id: 3, rows: sqlmock.NewRows(cols).AddRow(3, "Chetan", "Bhagat", "2001-04-06", "Chetan", 1),
+			resp: models.Author{AuthID: 3, FirstName: "Chetan", LastName: "Bhagat", Dob: models.NewDate(2001, 4, 6),
+				PenName: "Chetan", Version: 1}},
+		{desc: "error in exec", id: 4, patch: models.AuthorPatch{PenName: &penName},
+			query: "UPDATE Author SET penName=?, version=version+1 WHERE authorId=?", args: []driver.Value{penName, 4},
+			err: errors.New("error")},
+		{desc: "at the version", id: 5, version: 2, patch: models.AuthorPatch{PenName: &penName},
+			query: "UPDATE Author SET penName=?, version=version+1 WHERE authorId=? AND version=?",
+			args:  []driver.Value{penName, 5, 2},
+			rows:  sqlmock.NewRows(cols).AddRow(5, "Chetan", "Bhagat", "2001-04-06", penName, 3),
+			resp: models.Author{AuthID: 5, FirstName: "Chetan", LastName: "Bhagat", Dob: models.NewDate(2001, 4, 6),
+				PenName: penName, Version: 3}},
+		{desc: "version moved on", id: 6, version: 2, patch: models.AuthorPatch{PenName: &penName},
+			query: "UPDATE Author SET penName=?, version=version+1 WHERE authorId=? AND version=?",
+			args:  []driver.Value{penName, 6, 2}, moved: true, err: sql.ErrNoRows},
+		{desc: "empty patch on a moved on version", id: 7, version: 2,
+			rows: sqlmock.NewRows(cols).AddRow(7, "Chetan", "Bhagat", "2001-04-06", "Chetan", 3), err: sql.ErrNoRows},
+	}
+
+	// Customize SQL query matching
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	if err != nil {
+		log.Printf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+
+	app := gofr.New()
+	app.DB().DB = db
+
+	ctx := gofr.NewContext(nil, nil, app)
+
+	// Closing DB after all things done
+	defer db.Close()
+
+	for i, v := range testcases {
+		// Mocking Exec for updating the patched columns only
+		if v.query != "" {
+			exec := mock.ExpectExec(v.query).WithArgs(v.args...)
+
+			if v.moved {
+				exec.WillReturnResult(sqlmock.NewResult(0, 0))
+			} else {
+				exec.WillReturnResult(sqlmock.NewResult(0, 1)).WillReturnError(v.err)
+			}
+		}
+
+		// Mocking Query for reading back the author
+		if v.rows != nil {
+			mock.ExpectQuery("select authorId, firstName, lastName, dob, penName, version from Author where authorId=?").WithArgs(v.id).WillReturnRows(v.rows)
+		}
+
+		datastore := New()
+
+		resp, err := datastore.Patch(ctx, v.id, v.patch, v.version)
+
+		// Comparing body
+		if !reflect.DeepEqual(resp, v.resp) {
+			t.Errorf("desc : %v ,[TEST%d]Failed. Got %v\tExpected %v\n", v.desc, i+1, resp, v.resp)
+		}
+
+		if !reflect.DeepEqual(err, v.err) {
+			t.Errorf("desc : %v ,[TEST%d]Failed. Got %v\tExpected %v\n", v.desc, i+1, err, v.err)
 		}
 	}
 }
@@ -118,15 +469,18 @@ func TestAuthor_Put(t *testing.T) {
 // Testing Delete Author
 func TestAuthor_Delete(t *testing.T) {
 	testcases := []struct {
-		desc string
-		id   int
-		resp int
-		res  driver.Result
-		err  error
+		desc    string
+		id      int
+		version int
+		resp    int
+		res     driver.Result
+		err     error
 	}{
 		{desc: "valid", id: 1, resp: 1, res: sqlmock.NewResult(0, 1)},
 		{desc: "id not exist", id: 11, res: sqlmock.NewResult(0, 0), err: errors.New("error")},
 		{desc: "inserted id error", id: 4, res: sqlmock.NewErrorResult(errors.New("error"))},
+		{desc: "at the version", id: 5, version: 2, resp: 1, res: sqlmock.NewResult(0, 1)},
+		{desc: "version moved on", id: 6, version: 2, res: sqlmock.NewResult(0, 0)},
 	}
 
 	// Customize SQL query matching
@@ -145,12 +499,16 @@ func TestAuthor_Delete(t *testing.T) {
 
 	for i, v := range testcases {
 		// Mocking Exec for deleting author
-		mock.ExpectExec("delete from Author where authorId=?").WithArgs(v.id).
-			WillReturnResult(v.res).WillReturnError(v.err)
+		query, args := "delete from Author where authorId=?", []driver.Value{v.id}
+		if v.version > 0 {
+			query, args = query+" AND version=?", append(args, v.version)
+		}
+
+		mock.ExpectExec(query).WithArgs(args...).WillReturnResult(v.res).WillReturnError(v.err)
 
 		datastore := New()
 
-		resp, err := datastore.Delete(ctx, v.id)
+		resp, err := datastore.Delete(ctx, v.id, v.version)
 
 		if !reflect.DeepEqual(resp, v.resp) {
 			t.Errorf("desc : %v ,[TEST%d]Failed. Got %v\tExpected %v\n", v.desc, i+1, resp, v.resp)
@@ -171,10 +529,11 @@ func TestIncludeAuthor(t *testing.T) {
 		rows *sqlmock.Rows
 		err  error
 	}{
-		{desc: "valid", id: 1, resp: models.Author{AuthID: 1, FirstName: "Chetan", LastName: "Bhagat", Dob: "06/04/2001",
-			PenName: "Chetan"}, rows: sqlmock.NewRows([]string{"authorId", "fistName", "lastName", "dob", "penName"}).
-			AddRow(1, "Chetan", "Bhagat", "06/04/2001", "Chetan")},
-		{desc: "id not exist", id: 11, resp: models.Author{}, rows: sqlmock.NewRows([]string{"authorId", "fistName", "lastName", "dob", "penName"})},
+		{desc: "valid", id: 1, resp: models.Author{AuthID: 1, FirstName: "Chetan", LastName: "Bhagat",
+			Dob: models.NewDate(2001, 4, 6), PenName: "Chetan", Version: 1},
+			rows: sqlmock.NewRows([]string{"authorId", "fistName", "lastName", "dob", "penName", "version"}).
+				AddRow(1, "Chetan", "Bhagat", "2001-04-06", "Chetan", 1)},
+		{desc: "id not exist", id: 11, resp: models.Author{}, rows: sqlmock.NewRows([]string{"authorId", "fistName", "lastName", "dob", "penName", "version"})},
 	}
 
 	// Customize SQL query matching
@@ -193,7 +552,7 @@ func TestIncludeAuthor(t *testing.T) {
 
 	for i, v := range testcases {
 		// Mocking Exec for deleting author
-		mock.ExpectQuery("select * from Author where authorId=?").WithArgs(v.id).
+		mock.ExpectQuery("select authorId, firstName, lastName, dob, penName, version from Author where authorId=?").WithArgs(v.id).
 			WillReturnRows(v.rows).WillReturnError(v.err)
 
 		datastore := New()
@@ -210,7 +569,70 @@ func TestIncludeAuthor(t *testing.T) {
 	}
 }
 
-func Test_IsAuthorIDPresent(t *testing.T) {
+// TestIncludeAuthors
+func TestIncludeAuthors(t *testing.T) {
+	testcases := []struct {
+		desc  string
+		ids   []int
+		query string
+		args  []driver.Value
+		resp  map[int]models.Author
+		rows  *sqlmock.Rows
+		err   error
+	}{
+		{desc: "valid", ids: []int{1, 2}, query: "select authorId, firstName, lastName, dob, penName, version from Author where authorId in (?,?)",
+			args: []driver.Value{1, 2}, resp: map[int]models.Author{
+				1: {AuthID: 1, FirstName: "Chetan", LastName: "Bhagat", Dob: models.NewDate(2001, 4, 6),
+					PenName: "Chetan", Version: 1},
+				2: {AuthID: 2, FirstName: "Rajan", LastName: "Sharma", Dob: models.NewDate(2001, 4, 26),
+					PenName: "Rajan", Version: 1}},
+			rows: sqlmock.NewRows([]string{"authorId", "fistName", "lastName", "dob", "penName", "version"}).
+				AddRow(1, "Chetan", "Bhagat", "2001-04-06", "Chetan", 1).
+				AddRow(2, "Rajan", "Sharma", "2001-04-26", "Rajan", 1)},
+		{desc: "no ids", resp: map[int]models.Author{}},
+		{desc: "error in scanning", ids: []int{1}, query: "select authorId, firstName, lastName, dob, penName, version from Author where authorId in (?)",
+			args: []driver.Value{1}, rows: sqlmock.NewRows([]string{"authorId", "fistName", "lastName", "dob", "penName", "version"}).
+				AddRow("abc", "Chetan", "Bhagat", "2001-04-06", "Chetan", 1)},
+		{desc: "error in select", ids: []int{1}, query: "select authorId, firstName, lastName, dob, penName, version from Author where authorId in (?)",
+			args: []driver.Value{1}, rows: sqlmock.NewRows([]string{}), err: errors.New("error")},
+	}
+
+	// Customize SQL query matching
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	if err != nil {
+		log.Printf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+
+	app := gofr.New()
+	app.DB().DB = db
+
+	ctx := gofr.NewContext(nil, nil, app)
+
+	// Closing DB after all things done
+	defer db.Close()
+
+	for i, v := range testcases {
+		// Mocking select of authors
+		if v.query != "" {
+			mock.ExpectQuery(v.query).WithArgs(v.args...).WillReturnRows(v.rows).WillReturnError(v.err)
+		}
+
+		datastore := New()
+
+		resp, err := datastore.IncludeAuthors(ctx, v.ids)
+
+		if !reflect.DeepEqual(resp, v.resp) {
+			t.Errorf("desc : %v ,[TEST%d]Failed. Got %v\tExpected %v\n", v.desc, i+1, resp, v.resp)
+		}
+
+		if err != nil {
+			log.Printf("desc : %v ,[TEST%d]Failed. Got %v", v.desc, i+1, err)
+		}
+	}
+}
+
+// Test_Exists is to check for author existence, a failure of the DB is not a missing author
+func Test_Exists(t *testing.T) {
 	testCases := []struct {
 		desc string
 		id   int
@@ -218,9 +640,9 @@ func Test_IsAuthorIDPresent(t *testing.T) {
 		resp bool
 		err  error
 	}{
-		{desc: "valid", id: 1, resp: false, rows: sqlmock.NewRows([]string{"authorId", "fistName", "lastName", "dob", "penName"}).
-			AddRow(1, "Chetan", "Bhagat", "06/04/2001", "Chetan")},
-		{desc: "id not exist", id: 10, rows: sqlmock.NewRows([]string{}), resp: true},
+		{desc: "exists", id: 1, resp: true, rows: sqlmock.NewRows([]string{"1"}).AddRow(1)},
+		{desc: "id not exist", id: 10, rows: sqlmock.NewRows([]string{"1"})},
+		{desc: "error in query", id: 11, rows: sqlmock.NewRows([]string{"1"}), err: errors.New("connection refused")},
 	}
 
 	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
@@ -236,13 +658,19 @@ func Test_IsAuthorIDPresent(t *testing.T) {
 	defer db.Close()
 
 	for i, v := range testCases {
-		mock.ExpectQuery("select * from Author where authorId=?").WithArgs(v.id).WillReturnRows(v.rows).WillReturnError(v.err)
+		mock.ExpectQuery("SELECT 1 FROM Author WHERE authorId=? LIMIT 1").WithArgs(v.id).WillReturnRows(v.rows).
+			WillReturnError(v.err)
+
 		datastore := New()
 
-		resp := datastore.IsAuthorIDPresent(ctx, v.id)
+		resp, err := datastore.Exists(ctx, v.id)
 
 		if !reflect.DeepEqual(resp, v.resp) {
 			t.Errorf("desc : %v ,[TEST%d]Failed. Got %v\tExpected %v\n", v.desc, i+1, resp, v.resp)
 		}
+
+		if !reflect.DeepEqual(err, v.err) {
+			t.Errorf("desc : %v ,[TEST%d]Failed. Got %v\tExpected %v\n", v.desc, i+1, err, v.err)
+		}
 	}
 }
@@ -1,33 +1,91 @@
 package book
 
 import (
+	"database/sql"
+	"strings"
+
 	"developer.zopsmart.com/go/gofr/pkg/gofr"
+	"mytest/datastore"
 	"mytest/models"
+	"mytest/models/errors"
 )
 
 type Datastore struct {
+	// tx is the transaction the statements run in, the DB of the context when nil
+	tx *sql.Tx
 }
 
 func New() Datastore {
 	return Datastore{}
 }
 
+// NewTx is to build the book Datastore running its statements in the given transaction
+func NewTx(tx *sql.Tx) Datastore {
+	return Datastore{tx: tx}
+}
+
+// db is to get where the statements run, the transaction of the datastore or else the DB of the context
+func (d Datastore) db(c *gofr.Context) datastore.Executor {
+	if d.tx != nil {
+		return d.tx
+	}
+
+	return c.DB()
+}
+
+// conflict is to map a clash with the ISBN or the title, author and publication of another book to a Conflict
+func conflict(err error) error {
+	return datastore.Conflict(err, errors.CodeBookExists, "a book with the same ISBN, or title, author and "+
+		"publication already exists")
+}
+
 // Post method is to Post data in Book
 func (d Datastore) Post(c *gofr.Context, book *models.Book) (models.Book, error) {
-	// inserting data into Db
-	_, err := c.DB().Exec("insert into Book(bookId,title,authorId,Publication,PublishedDate) values (?,?,?,?,?)",
-		book.BookID, book.Title, book.AuthorID, book.Publication, book.PublishedDate)
+	// inserting data into Db, bookId is generated by AUTO_INCREMENT
+	res, err := d.db(c).Exec("insert into Book(title,authorId,Publication,PublishedDate,isbn) values (?,?,?,?,?)",
+		book.Title, book.AuthorID, book.Publication, book.PublishedDate, book.ISBN)
+	if err != nil {
+		return models.Book{}, conflict(err)
+	}
+
+	id, err := res.LastInsertId()
 	if err != nil {
 		return models.Book{}, err
 	}
 
+	book.BookID = int(id)
+	// a new book is at the first version, the default of the column
+	book.Version = 1
+
 	return *book, nil
 }
 
-// GetAll method is to get all Books with Author
-func (d Datastore) GetAll(c *gofr.Context) ([]models.Book, error) {
-	// reading all books from Db
-	allRows, err := c.DB().Query("SELECT * FROM Book")
+// GetAll method is to get a page of Books matching the filter in the given order, bookId by default
+func (d Datastore) GetAll(c *gofr.Context, filter models.BookFilter, sort []models.SortField,
+	page models.Page) ([]models.Book, error) {
+	if len(sort) == 0 {
+		sort = []models.SortField{{Field: "bookId"}}
+	}
+
+	for _, s := range sort {
+		if _, ok := sortColumns[s.Field]; !ok {
+			return nil, errors.InvalidParam{Code: errors.CodeInvalidParam, Params: []string{"sort"}}
+		}
+	}
+
+	q := filterQuery(filter)
+
+	if len(page.After) > 0 {
+		if len(page.After) != len(sort) {
+			return nil, errors.InvalidParam{Code: errors.CodeInvalidParam, Params: []string{"cursor"}}
+		}
+
+		q.after(sort, page.After)
+	}
+
+	// reading a page of books from Db
+	allRows, err := d.db(c).Query("SELECT "+bookColumns+" FROM Book"+q.clause()+orderBy(sort)+" LIMIT ? OFFSET ?",
+		append(q.args, page.Limit, page.Offset)...)
 	if err != nil {
 		return nil, err
 	}
@@ -40,9 +98,7 @@ func (d Datastore) GetAll(c *gofr.Context) ([]models.Book, error) {
 
 	// Iterating to each book
 	for allRows.Next() {
-		var b models.Book
-
-		err2 := allRows.Scan(&b.BookID, &b.Title, &b.AuthorID, &b.Publication, &b.PublishedDate)
+		b, err2 := scanBook(allRows)
 		if err2 != nil {
 			return []models.Book{}, err2
 		}
@@ -56,80 +112,224 @@ func (d Datastore) GetAll(c *gofr.Context) ([]models.Book, error) {
 // GetByID method is to get book by its ID
 func (d Datastore) GetByID(c *gofr.Context, id int) (models.Book, error) {
 	// reading all data of book with given id
-	row := c.DB().QueryRow("select * from Book where bookId=?", id)
+	row := d.db(c).QueryRow("select "+bookColumns+" from Book where bookId=?", id)
 
-	// to store d book
-	var book models.Book
+	return scanBook(row)
+}
 
-	// fetching data of book at given id and storing in book
-	if err := row.Scan(&book.BookID, &book.Title, &book.AuthorID, &book.Publication, &book.PublishedDate); err != nil {
+// GetByISBN method is to get the book by its ISBN-13, sql.ErrNoRows is returned when there is none
+func (d Datastore) GetByISBN(c *gofr.Context, isbn models.ISBN) (models.Book, error) {
+	row := d.db(c).QueryRow("select "+bookColumns+" from Book where isbn=?", isbn)
+
+	return scanBook(row)
+}
+
+// GetDuplicate method is to get a stored book other than the one with the given id with the title, author and
+// publication of the given one, only among the books without ISBN unless anyISBN. sql.ErrNoRows is returned
+// when there is none.
+func (d Datastore) GetDuplicate(c *gofr.Context, id int, book *models.Book, anyISBN bool) (models.Book, error) {
+	query := "select " + bookColumns + " from Book where title=? AND authorId=? AND Publication=? AND bookId<>?"
+	if !anyISBN {
+		query += " AND isbn IS NULL"
+	}
+
+	row := d.db(c).QueryRow(query+" LIMIT 1", book.Title, book.AuthorID, book.Publication, id)
+
+	return scanBook(row)
+}
+
+// Update method is to change data of Particular book and read it back. When a version is given the book is
+// changed only if it is still at that version, sql.ErrNoRows is returned otherwise.
+func (d Datastore) Update(c *gofr.Context, id int, book *models.Book, version int) (models.Book, error) {
+	res, err := d.db(c).Exec("UPDATE Book SET title=?, authorId=?, Publication=? , PublishedDate=?, isbn=?, "+
+		"version=version+1 WHERE bookId=?"+datastore.VersionClause(version),
+		datastore.VersionArgs([]interface{}{book.Title, book.AuthorID, book.Publication, book.PublishedDate, book.ISBN,
+			id}, version)...)
+	if err != nil {
+		return models.Book{}, conflict(err)
+	}
+
+	if err := datastore.CheckVersion(res, version); err != nil {
 		return models.Book{}, err
 	}
 
-	return book, nil
+	// reading back the stored book
+	return d.GetByID(c, id)
+}
+
+// Patch method is to change only the given fields of a book and read it back, a given version is honoured
+// as in Update
+func (d Datastore) Patch(c *gofr.Context, id int, patch models.BookPatch, version int) (models.Book, error) {
+	var (
+		set  []string
+		args []interface{}
+	)
+
+	if patch.Title != nil {
+		set = append(set, "title=?")
+		args = append(args, *patch.Title)
+	}
+
+	if patch.AuthorID != nil {
+		set = append(set, "authorId=?")
+		args = append(args, *patch.AuthorID)
+	}
+
+	if patch.Publication != nil {
+		set = append(set, "Publication=?")
+		args = append(args, *patch.Publication)
+	}
+
+	if patch.PublishedDate != nil {
+		set = append(set, "PublishedDate=?")
+		args = append(args, *patch.PublishedDate)
+	}
+
+	if patch.ISBN != nil {
+		set = append(set, "isbn=?")
+		args = append(args, *patch.ISBN)
+	}
+
+	if len(set) == 0 {
+		return d.getVersion(c, id, version)
+	}
+
+	res, err := d.db(c).Exec("UPDATE Book SET "+strings.Join(set, ",")+", version=version+1 WHERE bookId=?"+
+		datastore.VersionClause(version), datastore.VersionArgs(append(args, id), version)...)
+	if err != nil {
+		return models.Book{}, conflict(err)
+	}
+
+	if err := datastore.CheckVersion(res, version); err != nil {
+		return models.Book{}, err
+	}
+
+	return d.GetByID(c, id)
 }
 
-// Update method is to change data of Particular book
-func (d Datastore) Update(c *gofr.Context, id int, book *models.Book) (models.Book, error) {
-	_, err := c.DB().Exec("UPDATE Book SET title=?, Publication=? , PublishedDate=? WHERE bookId=?",
-		book.Title, book.Publication, book.PublishedDate, id)
+// getVersion is to read the book back as it is, sql.ErrNoRows is returned when it is not at the given version
+func (d Datastore) getVersion(c *gofr.Context, id, version int) (models.Book, error) {
+	book, err := d.GetByID(c, id)
 	if err != nil {
 		return models.Book{}, err
 	}
 
-	return *book, nil
+	if version > 0 && book.Version != version {
+		return models.Book{}, sql.ErrNoRows
+	}
+
+	return book, nil
 }
 
-// Delete method is remove Book by its ID
-func (d Datastore) Delete(c *gofr.Context, id int) (int, error) {
-	res, err := c.DB().Exec("DELETE FROM Book where bookId=?", id)
+// Delete method is remove Book by its ID, when a version is given only if the book is still at it
+func (d Datastore) Delete(c *gofr.Context, id, version int) (int, error) {
+	res, err := d.db(c).Exec("DELETE FROM Book where bookId=?"+datastore.VersionClause(version),
+		datastore.VersionArgs([]interface{}{id}, version)...)
 	if err != nil {
 		return 0, err
 	}
 
 	rowAffected, err2 := res.RowsAffected()
 	if err2 != nil {
+		return 0, err2
+	}
+
+	return int(rowAffected), nil
+}
+
+// DeleteByAuthorID method is to delete all the books of an author
+func (d Datastore) DeleteByAuthorID(c *gofr.Context, authorID int) (int, error) {
+	res, err := d.db(c).Exec("DELETE FROM Book where authorId=?", authorID)
+	if err != nil {
+		return 0, err
+	}
+
+	rowAffected, err := res.RowsAffected()
+	if err != nil {
+		return 0, err
+	}
+
+	return int(rowAffected), nil
+}
+
+// ReassignAuthor method is to move all the books of an author to another author
+func (d Datastore) ReassignAuthor(c *gofr.Context, from, to int) (int, error) {
+	res, err := d.db(c).Exec("UPDATE Book SET authorId=?, version=version+1 where authorId=?", to, from)
+	if err != nil {
+		return 0, conflict(err)
+	}
+
+	rowAffected, err := res.RowsAffected()
+	if err != nil {
 		return 0, err
 	}
 
 	return int(rowAffected), nil
 }
 
-// GetBookByTitle method is to get all the books according to given title
-func (d Datastore) GetBookByTitle(c *gofr.Context, title string) ([]models.Book, error) {
-	rows, err := c.DB().Query("select * from Book where title=?", title)
+// TouchPublication method is to bump the version of all the books of a publication, which is what renaming
+// its publisher changes along with their publication through the ON UPDATE CASCADE of fk_book_publisher
+func (d Datastore) TouchPublication(c *gofr.Context, publication string) (int, error) {
+	res, err := d.db(c).Exec("UPDATE Book SET version=version+1 where Publication=?", publication)
 	if err != nil {
-		return []models.Book{}, err
+		return 0, err
 	}
 
-	// Closing rows
-	defer rows.Close()
+	rowAffected, err := res.RowsAffected()
+	if err != nil {
+		return 0, err
+	}
+
+	return int(rowAffected), nil
+}
 
-	var books []models.Book
+// Count method is to count all the books matching the filter
+func (d Datastore) Count(c *gofr.Context, filter models.BookFilter) (int, error) {
+	q := filterQuery(filter)
 
-	// Iterate to all books
-	for rows.Next() {
-		var b models.Book
-		err := rows.Scan(&b.BookID, &b.Title, &b.AuthorID, &b.Publication, &b.PublishedDate)
-		if err != nil {
-			return []models.Book{}, err
-		}
+	var total int
 
-		books = append(books, b)
+	if err := d.db(c).QueryRow("SELECT count(*) FROM Book"+q.clause(), q.args...).Scan(&total); err != nil {
+		return 0, err
 	}
 
-	return books, nil
+	return total, nil
 }
 
-// IsBookPresent method is to find weather a book is present or not
-func (d Datastore) IsBookPresent(c *gofr.Context, id int) bool {
-	var book models.Book
+// Exists method is to check whether the book is stored, an error is returned only when the DB fails
+func (d Datastore) Exists(c *gofr.Context, id int) (bool, error) {
+	var one int
 
-	row := c.DB().QueryRow("select * from Book where bookId=?", id)
+	err := d.db(c).QueryRow("SELECT 1 FROM Book WHERE bookId=? LIMIT 1", id).Scan(&one)
 
-	if err := row.Scan(&book.BookID, &book.Title, &book.AuthorID, &book.Publication, &book.PublishedDate); err != nil {
-		return true
+	switch {
+	case err == sql.ErrNoRows:
+		return false, nil
+	case err != nil:
+		return false, err
 	}
 
-	return false
+	return true, nil
+}
+
+// bookFields is the mapping of the Book columns to the fields of book
+func bookFields(book *models.Book) []datastore.Field {
+	return []datastore.Field{{Column: "bookId", Dest: &book.BookID}, {Column: "title", Dest: &book.Title},
+		{Column: "authorId", Dest: &book.AuthorID}, {Column: "Publication", Dest: &book.Publication},
+		{Column: "PublishedDate", Dest: &book.PublishedDate}, {Column: "isbn", Dest: &book.ISBN},
+		{Column: "version", Dest: &book.Version}}
+}
+
+// bookColumns is the column list every read of a book selects
+var bookColumns = datastore.Columns(bookFields(&models.Book{}))
+
+// scanBook is to scan a single Book row selected with bookColumns
+func scanBook(row datastore.Row) (models.Book, error) {
+	var book models.Book
+
+	if err := datastore.Scan(row, bookFields(&book)); err != nil {
+		return models.Book{}, err
+	}
+
+	return book, nil
 }
@@ -1,6 +1,7 @@
 package book
 
 import (
+	"database/sql"
 	"database/sql/driver"
 	"developer.zopsmart.com/go/gofr/pkg/gofr"
 	"errors"
@@ -9,28 +10,47 @@ import (
 	"testing"
 
 	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/go-sql-driver/mysql"
 
 	"mytest/models"
+	modelErrors "mytest/models/errors"
 )
 
 // Test_Post Book
 func Test_Post(t *testing.T) {
 	testcases := []struct {
-		desc         string
-		req          models.Book
-		response     models.Book
-		lastInsertID int64
-		rowAffected  int64
-		err          error
+		desc     string
+		req      models.Book
+		response models.Book
+		result   driver.Result
+		execErr  error
+		err      error
 	}{
-		{desc: "valid details", req: models.Book{BookID: 1, AuthorID: 1,
-			Auth:  models.Author{AuthID: 1, FirstName: "Chetan", LastName: "Bhagat", Dob: "06/04/2001", PenName: "Chetan"},
-			Title: "2 States", Publication: "Scholastic", PublishedDate: "16/03/2016"}, response: models.Book{BookID: 1,
-			AuthorID: 1, Auth: models.Author{AuthID: 1, FirstName: "Chetan", LastName: "Bhagat", Dob: "06/04/2001", PenName: "Chetan"},
-			Title: "2 States", Publication: "Scholastic", PublishedDate: "16/03/2016"}, lastInsertID: 1, rowAffected: 1},
-		{desc: "duplicate id", req: models.Book{BookID: 1, AuthorID: 1,
-			Auth:  models.Author{AuthID: 1, FirstName: "Chetan", LastName: "Bhagat", Dob: "06/04/2001", PenName: "Chetan"},
-			Title: "2 States", Publication: "Scholastic", PublishedDate: "16/03/2016"}, err: errors.New(" Duplicate entry '1' for key 'PRIMARY'")},
+		{desc: "valid details", req: models.Book{AuthorID: 1,
+			Auth: models.Author{AuthID: 1, FirstName: "Chetan", LastName: "Bhagat", Dob: models.NewDate(2001, 4, 6),
+				PenName: "Chetan"},
+			Title: "2 States", Publication: "Scholastic", PublishedDate: models.NewDate(2016, 3, 16)},
+			response: models.Book{BookID: 1,
+				AuthorID: 1, Auth: models.Author{AuthID: 1, FirstName: "Chetan", LastName: "Bhagat",
+					Dob: models.NewDate(2001, 4, 6), PenName: "Chetan"},
+				Title: "2 States", Publication: "Scholastic", PublishedDate: models.NewDate(2016, 3, 16), Version: 1},
+			result: sqlmock.NewResult(1, 1)},
+		{desc: "error in insert", req: models.Book{AuthorID: 1,
+			Auth: models.Author{AuthID: 1, FirstName: "Chetan", LastName: "Bhagat", Dob: models.NewDate(2001, 4, 6),
+				PenName: "Chetan"},
+			Title: "2 States", Publication: "Scholastic", PublishedDate: models.NewDate(2016, 3, 16)},
+			result:  sqlmock.NewResult(0, 0),
+			execErr: errors.New("error in insert"), err: errors.New("error in insert")},
+		{desc: "error in lastInsertId", req: models.Book{AuthorID: 1,
+			Auth: models.Author{AuthID: 1, FirstName: "Chetan", LastName: "Bhagat", Dob: models.NewDate(2001, 4, 6),
+				PenName: "Chetan"},
+			Title: "2 States", Publication: "Scholastic", PublishedDate: models.NewDate(2016, 3, 16)},
+			result: sqlmock.NewErrorResult(errors.New("error in lastInsertId")), err: errors.New("error in lastInsertId")},
+		{desc: "duplicate key", req: models.Book{AuthorID: 1, Title: "2 States", Publication: "Scholastic",
+			PublishedDate: models.NewDate(2016, 3, 16), ISBN: "9780306406157"}, result: sqlmock.NewResult(0, 0),
+			execErr: &mysql.MySQLError{Number: 1062, Message: "Duplicate entry for key 'Book.uq_book_isbn'"},
+			err: modelErrors.Conflict{Code: modelErrors.CodeBookExists,
+				Reason: "a book with the same ISBN, or title, author and publication already exists"}},
 	}
 
 	// Customize SQL query matching
@@ -49,9 +69,9 @@ func Test_Post(t *testing.T) {
 
 	for i, v := range testcases {
 		// Mocking insert query for book
-		mock.ExpectExec("insert into Book(bookId,title,authorId,Publication,PublishedDate) values (?,?,?,?,?)").
-			WithArgs(v.req.BookID, v.req.Title, v.req.AuthorID, v.req.Publication, v.req.PublishedDate).
-			WillReturnResult(sqlmock.NewResult(v.lastInsertID, v.rowAffected)).WillReturnError(v.err)
+		mock.ExpectExec("insert into Book(title,authorId,Publication,PublishedDate,isbn) values (?,?,?,?,?)").
+			WithArgs(v.req.Title, v.req.AuthorID, v.req.Publication, v.req.PublishedDate, v.req.ISBN).
+			WillReturnResult(v.result).WillReturnError(v.execErr)
 
 		// injecting mock db
 		datastore := New()
@@ -70,27 +90,112 @@ func Test_Post(t *testing.T) {
 	}
 }
 
+// Test_PostTx is to check that a datastore built on a transaction inserts the book in it
+func Test_PostTx(t *testing.T) {
+	book := models.Book{AuthorID: 1, Title: "2 States", Publication: "Scholastic",
+		PublishedDate: models.NewDate(2016, 3, 16), Version: 1}
+
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	if err != nil {
+		log.Printf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+
+	app := gofr.New()
+	app.DB().DB = db
+
+	ctx := gofr.NewContext(nil, nil, app)
+
+	defer db.Close()
+
+	mock.ExpectBegin()
+	mock.ExpectExec("insert into Book(title,authorId,Publication,PublishedDate,isbn) values (?,?,?,?,?)").
+		WithArgs(book.Title, book.AuthorID, book.Publication, book.PublishedDate, book.ISBN).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectRollback()
+
+	tx, err := db.Begin()
+	if err != nil {
+		t.Fatalf("desc : insert in transaction ,Failed. Got %v\n", err)
+	}
+
+	resp, err := NewTx(tx).Post(ctx, &book)
+	if resp.BookID != 1 || err != nil {
+		t.Errorf("desc : insert in transaction ,Failed. Got %v, %v\tExpected bookId 1\n", resp, err)
+	}
+
+	if err := tx.Rollback(); err != nil {
+		t.Errorf("desc : insert in transaction ,Failed. Got %v\n", err)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("desc : insert in transaction ,Failed. Got %v\n", err)
+	}
+}
+
 // Test_GetAll all book
 func Test_GetAll(t *testing.T) {
 	testcases := []struct {
-		desc string
-		resp []models.Book
-		rows *sqlmock.Rows
-		err  error
+		desc  string
+		sort  []models.SortField
+		page  models.Page
+		query string
+		args  []driver.Value
+		resp  []models.Book
+		rows  *sqlmock.Rows
+		err   error
 	}{
-		{desc: "valid details ", resp: []models.Book{
-			{BookID: 1, AuthorID: 1,
-				Title: "States", Publication: "Scholastic", PublishedDate: "16/03/2016"},
-			{BookID: 2, AuthorID: 1,
-				Title: "3 States", Publication: "Penguin", PublishedDate: "11/03/2016"}},
-			rows: sqlmock.NewRows([]string{"bookId", "title", "authorId", "Publication", "PublishedDate"}).
-				AddRow(1, "States", 1, "Scholastic", "16/03/2016").
-				AddRow(2, "3 States", 1, "Penguin", "11/03/2016"),
+		{desc: "valid details ", page: models.Page{Limit: 21},
+			query: "SELECT bookId, title, authorId, Publication, PublishedDate, isbn, version FROM Book ORDER BY bookId LIMIT ? OFFSET ?", args: []driver.Value{21, 0},
+			resp: []models.Book{
+				{BookID: 1, AuthorID: 1,
+					Title: "States", Publication: "Scholastic", PublishedDate: models.NewDate(2016, 3, 16), Version: 1},
+				{BookID: 2, AuthorID: 1,
+					Title: "3 States", Publication: "Penguin", PublishedDate: models.NewDate(2016, 3, 11), Version: 1}},
+			rows: sqlmock.NewRows([]string{"bookId", "title", "authorId", "Publication", "PublishedDate", "isbn", "version"}).
+				AddRow(1, "States", 1, "Scholastic", "2016-03-16", nil, 1).
+				AddRow(2, "3 States", 1, "Penguin", "2016-03-11", nil, 1),
+		},
+		{desc: "page after cursor", page: models.Page{Limit: 2, After: []string{"1"}},
+			query: "SELECT bookId, title, authorId, Publication, PublishedDate, isbn, version FROM Book WHERE ((bookId>?)) ORDER BY bookId LIMIT ? OFFSET ?", args: []driver.Value{"1", 2, 0},
+			resp: []models.Book{
+				{BookID: 2, AuthorID: 1, Title: "3 States", Publication: "Penguin",
+					PublishedDate: models.NewDate(2016, 3, 11), Version: 1}},
+			rows: sqlmock.NewRows([]string{"bookId", "title", "authorId", "Publication", "PublishedDate", "isbn", "version"}).
+				AddRow(2, "3 States", 1, "Penguin", "2016-03-11", nil, 1),
+		},
+		{desc: "sorted", sort: []models.SortField{{Field: "title"}, {Field: "publishedDate", Desc: true}, {Field: "bookId"}},
+			page:  models.Page{Limit: 21},
+			query: "SELECT bookId, title, authorId, Publication, PublishedDate, isbn, version FROM Book ORDER BY title,PublishedDate DESC,bookId LIMIT ? OFFSET ?",
+			args:  []driver.Value{21, 0},
+			resp: []models.Book{
+				{BookID: 2, AuthorID: 1, Title: "3 States", Publication: "Penguin",
+					PublishedDate: models.NewDate(2016, 3, 11), Version: 1}},
+			rows: sqlmock.NewRows([]string{"bookId", "title", "authorId", "Publication", "PublishedDate", "isbn", "version"}).
+				AddRow(2, "3 States", 1, "Penguin", "2016-03-11", nil, 1),
 		},
-		{desc: "error in scanning", resp: []models.Book{}, rows: sqlmock.NewRows([]string{"bookId", "title",
-			"authorId", "Publication", "PublishedDate"}).AddRow("abc", "States", 1, "Scholastic", "16/03/2016"),
+		{desc: "sorted page after cursor", sort: []models.SortField{{Field: "title"}, {Field: "publishedDate", Desc: true},
+			{Field: "bookId"}}, page: models.Page{Limit: 21, After: []string{"3 States", "2016-03-11", "2"}},
+			query: "SELECT bookId, title, authorId, Publication, PublishedDate, isbn, version FROM Book WHERE ((title>?) OR (title=? AND PublishedDate<?) OR " +
+				"(title=? AND PublishedDate=? AND bookId>?)) ORDER BY title,PublishedDate DESC,bookId LIMIT ? OFFSET ?",
+			args: []driver.Value{"3 States", "3 States", "2016-03-11", "3 States", "2016-03-11", "2", 21, 0},
+			resp: []models.Book{
+				{BookID: 1, AuthorID: 1, Title: "States", Publication: "Scholastic",
+					PublishedDate: models.NewDate(2016, 3, 16), Version: 1}},
+			rows: sqlmock.NewRows([]string{"bookId", "title", "authorId", "Publication", "PublishedDate", "isbn", "version"}).
+				AddRow(1, "States", 1, "Scholastic", "2016-03-16", nil, 1),
 		},
-		{desc: "error in select all ", rows: sqlmock.NewRows([]string{}), err: errors.New("error in select all")},
+		{desc: "invalid sort field", sort: []models.SortField{{Field: "bookId;drop table Book"}},
+			page: models.Page{Limit: 21}, err: errors.New("invalid param")},
+		{desc: "cursor of another sort", sort: []models.SortField{{Field: "title"}, {Field: "bookId"}},
+			page: models.Page{Limit: 21, After: []string{"2"}}, err: errors.New("invalid param")},
+		{desc: "error in scanning", page: models.Page{Limit: 21},
+			query: "SELECT bookId, title, authorId, Publication, PublishedDate, isbn, version FROM Book ORDER BY bookId LIMIT ? OFFSET ?", args: []driver.Value{21, 0},
+			resp: []models.Book{}, rows: sqlmock.NewRows([]string{"bookId", "title",
+				"authorId", "Publication", "PublishedDate", "isbn", "version"}).AddRow("abc", "States", 1, "Scholastic", "2016-03-16", nil, 1),
+		},
+		{desc: "error in select all ", page: models.Page{Limit: 21, Offset: 20},
+			query: "SELECT bookId, title, authorId, Publication, PublishedDate, isbn, version FROM Book ORDER BY bookId LIMIT ? OFFSET ?", args: []driver.Value{21, 20},
+			rows: sqlmock.NewRows([]string{}), err: errors.New("error in select all")},
 	}
 
 	// Customize SQL query matching
@@ -109,12 +214,14 @@ func Test_GetAll(t *testing.T) {
 
 	for i, v := range testcases {
 		// Mocking select all books query
-		mock.ExpectQuery("SELECT * FROM Book").WillReturnRows(v.rows).WillReturnError(v.err)
+		if v.query != "" {
+			mock.ExpectQuery(v.query).WithArgs(v.args...).WillReturnRows(v.rows).WillReturnError(v.err)
+		}
 
 		// injecting mock db
 		datastore := New()
 
-		resp, err := datastore.GetAll(ctx)
+		resp, err := datastore.GetAll(ctx, models.BookFilter{}, v.sort, v.page)
 
 		// Comparing body
 		if !reflect.DeepEqual(resp, v.resp) {
@@ -137,10 +244,11 @@ func Test_GetByID(t *testing.T) {
 		err  error
 	}{
 		{desc: "valid", id: 1, resp: models.Book{BookID: 1, AuthorID: 1, Title: "States",
-			Publication: "Scholastic", PublishedDate: "16/03/2016"}, rows: sqlmock.NewRows([]string{"bookId", "title", "authorId", "Publication", "PublishedDate"}).
-			AddRow(1, "States", 1, "Scholastic", "16/03/2016")},
-		{desc: "error in scanning", id: 11, rows: sqlmock.NewRows([]string{"bookId", "title", "authorId", "Publication", "PublishedDate"}).
-			AddRow("ac", "States", 1, "Scholastic", "16/03/2016"),
+			Publication: "Scholastic", PublishedDate: models.NewDate(2016, 3, 16), Version: 1},
+			rows: sqlmock.NewRows([]string{"bookId", "title", "authorId", "Publication", "PublishedDate", "isbn", "version"}).
+				AddRow(1, "States", 1, "Scholastic", "2016-03-16", nil, 1)},
+		{desc: "error in scanning", id: 11, rows: sqlmock.NewRows([]string{"bookId", "title", "authorId", "Publication", "PublishedDate", "isbn", "version"}).
+			AddRow("ac", "States", 1, "Scholastic", "2016-03-16", nil, 1),
 			err: errors.New("error in scanning")},
 	}
 
@@ -160,7 +268,7 @@ func Test_GetByID(t *testing.T) {
 
 	for i, v := range testcases {
 		// Mocking Query for reading book
-		mock.ExpectQuery("select * from Book where bookId=?").WithArgs(v.id).
+		mock.ExpectQuery("select bookId, title, authorId, Publication, PublishedDate, isbn, version from Book where bookId=?").WithArgs(v.id).
 			WillReturnRows(v.rows).WillReturnError(v.err)
 
 		// Injecting mock DB
@@ -180,22 +288,141 @@ func Test_GetByID(t *testing.T) {
 	}
 }
 
-// Test_Put book
+// Test_GetByISBN Testing book Get by isbn
+func Test_GetByISBN(t *testing.T) {
+	testcases := []struct {
+		desc string
+		isbn models.ISBN
+		resp models.Book
+		rows *sqlmock.Rows
+		err  error
+	}{
+		{desc: "valid", isbn: "9780306406157", resp: models.Book{BookID: 1, AuthorID: 1, Title: "States",
+			Publication: "Scholastic", PublishedDate: models.NewDate(2016, 3, 16), ISBN: "9780306406157", Version: 1},
+			rows: sqlmock.NewRows([]string{"bookId", "title", "authorId", "Publication", "PublishedDate", "isbn", "version"}).
+				AddRow(1, "States", 1, "Scholastic", "2016-03-16", "9780306406157", 1)},
+		{desc: "isbn not exist", isbn: "9780804429573", rows: sqlmock.NewRows([]string{"bookId", "title", "authorId",
+			"Publication", "PublishedDate", "isbn", "version"}), err: sql.ErrNoRows},
+	}
+
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	if err != nil {
+		log.Printf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+
+	app := gofr.New()
+	app.DB().DB = db
+
+	ctx := gofr.NewContext(nil, nil, app)
+
+	defer db.Close()
+
+	for i, v := range testcases {
+		mock.ExpectQuery("select bookId, title, authorId, Publication, PublishedDate, isbn, version from Book where isbn=?").WithArgs(string(v.isbn)).WillReturnRows(v.rows)
+
+		datastore := New()
+
+		resp, err := datastore.GetByISBN(ctx, v.isbn)
+
+		if !reflect.DeepEqual(resp, v.resp) {
+			t.Errorf("Desc : %v,[TEST%d]Failed. Got %v\tExpected %v\n", v.desc, i+1, resp, v.resp)
+		}
+
+		if !reflect.DeepEqual(err, v.err) {
+			t.Errorf("desc : %v ,[TEST%d]Failed. Got %v\tExpected %v\n", v.desc, i+1, err, v.err)
+		}
+	}
+}
+
+// Test_GetDuplicate Testing book Get by title, author and publication
+func Test_GetDuplicate(t *testing.T) {
+	book := models.Book{AuthorID: 1, Title: "States", Publication: "Scholastic",
+		PublishedDate: models.NewDate(2017, 1, 1), Version: 1}
+	columns := []string{"bookId", "title", "authorId", "Publication", "PublishedDate", "isbn", "version"}
+	query := "select bookId, title, authorId, Publication, PublishedDate, isbn, version from Book " +
+		"where title=? AND authorId=? AND Publication=? AND bookId<>?"
+
+	testcases := []struct {
+		desc    string
+		id      int
+		anyISBN bool
+		query   string
+		resp    models.Book
+		rows    *sqlmock.Rows
+		err     error
+	}{
+		{desc: "duplicate without isbn", query: query + " AND isbn IS NULL LIMIT 1",
+			resp: models.Book{BookID: 1, AuthorID: 1, Title: "States", Publication: "Scholastic",
+				PublishedDate: models.NewDate(2016, 3, 16), Version: 1},
+			rows: sqlmock.NewRows(columns).AddRow(1, "States", 1, "Scholastic", "2016-03-16", nil, 1)},
+		{desc: "duplicate with any isbn", id: 4, anyISBN: true, query: query + " LIMIT 1",
+			resp: models.Book{BookID: 2, AuthorID: 1, Title: "States", Publication: "Scholastic",
+				PublishedDate: models.NewDate(2016, 3, 16), ISBN: "9780306406157", Version: 3},
+			rows: sqlmock.NewRows(columns).AddRow(2, "States", 1, "Scholastic", "2016-03-16", "9780306406157", 3)},
+		{desc: "no duplicate", query: query + " AND isbn IS NULL LIMIT 1", rows: sqlmock.NewRows(columns),
+			err: sql.ErrNoRows},
+	}
+
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	if err != nil {
+		log.Printf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+
+	app := gofr.New()
+	app.DB().DB = db
+
+	ctx := gofr.NewContext(nil, nil, app)
+
+	defer db.Close()
+
+	for i, v := range testcases {
+		mock.ExpectQuery(v.query).WithArgs(book.Title, book.AuthorID, book.Publication, v.id).WillReturnRows(v.rows)
+
+		datastore := New()
+
+		resp, err := datastore.GetDuplicate(ctx, v.id, &book, v.anyISBN)
+
+		if !reflect.DeepEqual(resp, v.resp) {
+			t.Errorf("Desc : %v,[TEST%d]Failed. Got %v\tExpected %v\n", v.desc, i+1, resp, v.resp)
+		}
+
+		if !reflect.DeepEqual(err, v.err) {
+			t.Errorf("desc : %v ,[TEST%d]Failed. Got %v\tExpected %v\n", v.desc, i+1, err, v.err)
+		}
+	}
+}
+
+// Test_Put function is to test the update of a book
 func Test_Put(t *testing.T) {
 	testcases := []struct {
-		desc   string
-		id     int
-		req    models.Book
-		resp   models.Book
-		result driver.Result
-		err    error
+		desc    string
+		id      int
+		version int
+		req     models.Book
+		resp    models.Book
+		result  driver.Result
+		err     error
 	}{
 		{desc: "valid", id: 1, req: models.Book{BookID: 1, AuthorID: 1,
-			Title: "300 Days", Publication: "Penguin", PublishedDate: "17/03/2016"}, result: sqlmock.NewResult(1, 1),
-			resp: models.Book{BookID: 1, AuthorID: 1, Title: "300 Days", Publication: "Penguin", PublishedDate: "17/03/2016"}},
+			Title: "300 Days", Publication: "Penguin", PublishedDate: models.NewDate(2016, 3, 17)},
+			result: sqlmock.NewResult(1, 1),
+			resp: models.Book{BookID: 1, AuthorID: 1, Title: "300 Days", Publication: "Penguin",
+				PublishedDate: models.NewDate(2016, 3, 17), Version: 2}},
+		{desc: "change author", id: 2, req: models.Book{AuthorID: 3, Title: "300 Days", Publication: "Penguin",
+			PublishedDate: models.NewDate(2016, 3, 17)}, result: sqlmock.NewResult(0, 1),
+			resp: models.Book{BookID: 2, AuthorID: 3, Title: "300 Days", Publication: "Penguin",
+				PublishedDate: models.NewDate(2016, 3, 17), Version: 2}},
+		{desc: "at the version", id: 3, version: 4, req: models.Book{AuthorID: 1, Title: "300 Days",
+			Publication: "Penguin", PublishedDate: models.NewDate(2016, 3, 17)}, result: sqlmock.NewResult(0, 1),
+			resp: models.Book{BookID: 3, AuthorID: 1, Title: "300 Days", Publication: "Penguin",
+				PublishedDate: models.NewDate(2016, 3, 17), Version: 5}},
+		{desc: "version moved on", id: 3, version: 4, req: models.Book{AuthorID: 1, Title: "300 Days",
+			Publication: "Penguin", PublishedDate: models.NewDate(2016, 3, 17)}, result: sqlmock.NewResult(0, 0),
+			err: sql.ErrNoRows},
 		{desc: "error in exec", id: 11, req: models.Book{BookID: 1, AuthorID: 1,
-			Title: "300 Days", Publication: "Penguin", PublishedDate: "17/03/2016"}, result: sqlmock.NewResult(0, 0),
-			err: errors.New("sql: no rows in result set")},
+			Title: "300 Days", Publication: "Penguin", PublishedDate: models.NewDate(2016, 3, 17)},
+			result: sqlmock.NewResult(0, 0),
+			err:    errors.New("error in exec")},
 	}
 
 	// Customize SQL query matching
@@ -213,22 +440,134 @@ func Test_Put(t *testing.T) {
 	defer db.Close()
 
 	for i, v := range testcases {
-		// Mocking Exec query for updating data
-		mock.ExpectExec("UPDATE Book SET title=?, Publication=? , PublishedDate=? WHERE bookId=?").
-			WithArgs(v.resp.Title, v.resp.Publication, v.resp.PublishedDate, v.id).
-			WillReturnResult(v.result).WillReturnError(v.err)
+		query := "UPDATE Book SET title=?, authorId=?, Publication=? , PublishedDate=?, isbn=?, version=version+1 WHERE bookId=?"
+		args := []driver.Value{v.req.Title, v.req.AuthorID, v.req.Publication, v.req.PublishedDate, v.req.ISBN, v.id}
+
+		if v.version > 0 {
+			query += " AND version=?"
+			args = append(args, v.version)
+		}
+
+		// Mocking Exec query for updating data, an error of the DB and a moved on version are told apart
+		exec := mock.ExpectExec(query).WithArgs(args...).WillReturnResult(v.result)
+		if v.err != sql.ErrNoRows {
+			exec.WillReturnError(v.err)
+		}
+
+		// Mocking Query for reading back the stored book
+		if v.err == nil {
+			mock.ExpectQuery("select bookId, title, authorId, Publication, PublishedDate, isbn, version from Book where bookId=?").WithArgs(v.id).
+				WillReturnRows(sqlmock.NewRows([]string{"bookId", "title", "authorId", "Publication", "PublishedDate", "isbn", "version"}).
+					AddRow(v.resp.BookID, v.resp.Title, v.resp.AuthorID, v.resp.Publication, v.resp.PublishedDate,
+						v.resp.ISBN, v.resp.Version))
+		}
 
 		// Injecting mock Db
 		datastore := New()
 
-		resp, err := datastore.Update(ctx, v.id, &v.req)
+		resp, err := datastore.Update(ctx, v.id, &v.req, v.version)
 
 		if !reflect.DeepEqual(resp, v.resp) {
 			t.Errorf("desc : %v ,[TEST%d]Failed. Got %v\tExpected %v\n", v.desc, i+1, resp, v.resp)
 		}
 
-		if err != nil {
-			log.Printf("desc : %v ,[TEST%d]Failed. Got %v\tExpected %v\n", v.desc, i+1, err, v.err)
+		if !reflect.DeepEqual(err, v.err) {
+			t.Errorf("desc : %v ,[TEST%d]Failed. Got %v\tExpected %v\n", v.desc, i+1, err, v.err)
+		}
+	}
+}
+
+// Test_Patch book
+func Test_Patch(t *testing.T) {
+	title, publication, date, authorID := "300 Days", "Penguin", models.NewDate(2016, 3, 17), 2
+	isbn := models.ISBN("9780306406157")
+	cols := []string{"bookId", "title", "authorId", "Publication", "PublishedDate", "isbn", "version"}
+
+	testcases := []struct {
+		desc    string
+		id      int
+		version int
+		patch   models.BookPatch
+		query   string
+		args    []driver.Value
+		// moved is whether the stored book is at another version than the given one
+		moved bool
+		rows  *sqlmock.Rows
+		resp  models.Book
+		err   error
+	}{
+		{desc: "title only", id: 1, patch: models.BookPatch{Title: &title},
+			query: "UPDATE Book SET title=?, version=version+1 WHERE bookId=?",
+			args:  []driver.Value{title, 1}, rows: sqlmock.NewRows(cols).AddRow(1, title, 1, "Scholastic", "2016-03-16", nil, 1),
+			resp: models.Book{BookID: 1, AuthorID: 1, Title: title, Publication: "Scholastic",
+				PublishedDate: models.NewDate(2016, 3, 16), Version: 1}},
+		{desc: "all fields", id: 2,
+			query: "UPDATE Book SET title=?,authorId=?,Publication=?,PublishedDate=?,isbn=?, version=version+1 WHERE bookId=?",
+			patch: models.BookPatch{AuthorID: &authorID, Title: &title, Publication: &publication, PublishedDate: &date,
+				ISBN: &isbn}, args: []driver.Value{title, authorID, publication, "2016-03-17", "9780306406157", 2},
+			rows: sqlmock.NewRows(cols).AddRow(2, title, authorID, publication, "2016-03-17", "9780306406157", 1),
+			resp: models.Book{BookID: 2, AuthorID: authorID, Title: title, Publication: publication, PublishedDate: date,
+				ISBN: isbn, Version: 1}},
+		{desc: "empty patch", id: 3, rows: sqlmock.NewRows(cols).AddRow(3, "States", 1, "Scholastic", "2016-03-16", nil, 1),
+			resp: models.Book{BookID: 3, AuthorID: 1, Title: "States", Publication: "Scholastic",
+				PublishedDate: models.NewDate(2016, 3, 16), Version: 1}},
+		{desc: "error in exec", id: 4, patch: models.BookPatch{Title: &title},
+			query: "UPDATE Book SET title=?, version=version+1 WHERE bookId=?", args: []driver.Value{title, 4},
+			err: errors.New("error in exec")},
+		{desc: "at the version", id: 5, version: 2, patch: models.BookPatch{Title: &title},
+			query: "UPDATE Book SET title=?, version=version+1 WHERE bookId=? AND version=?",
+			args:  []driver.Value{title, 5, 2},
+			rows:  sqlmock.NewRows(cols).AddRow(5, title, 1, "Scholastic", "2016-03-16", nil, 3),
+			resp: models.Book{BookID: 5, AuthorID: 1, Title: title, Publication: "Scholastic",
+				PublishedDate: models.NewDate(2016, 3, 16), Version: 3}},
+		{desc: "version moved on", id: 6, version: 2, patch: models.BookPatch{Title: &title},
+			query: "UPDATE Book SET title=?, version=version+1 WHERE bookId=? AND version=?",
+			args:  []driver.Value{title, 6, 2}, moved: true, err: sql.ErrNoRows},
+		{desc: "empty patch on a moved on version", id: 7, version: 2,
+			rows: sqlmock.NewRows(cols).AddRow(7, "States", 1, "Scholastic", "2016-03-16", nil, 3), err: sql.ErrNoRows},
+	}
+
+	// Customize SQL query matching
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	if err != nil {
+		log.Printf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+
+	app := gofr.New()
+	app.DB().DB = db
+
+	ctx := gofr.NewContext(nil, nil, app)
+
+	// Closing DB after all things done
+	defer db.Close()
+
+	for i, v := range testcases {
+		// Mocking Exec query for updating the patched columns only
+		if v.query != "" {
+			exec := mock.ExpectExec(v.query).WithArgs(v.args...)
+
+			if v.moved {
+				exec.WillReturnResult(sqlmock.NewResult(0, 0))
+			} else {
+				exec.WillReturnResult(sqlmock.NewResult(0, 1)).WillReturnError(v.err)
+			}
+		}
+
+		// Mocking Query for reading back the book
+		if v.rows != nil {
+			mock.ExpectQuery("select bookId, title, authorId, Publication, PublishedDate, isbn, version from Book where bookId=?").WithArgs(v.id).WillReturnRows(v.rows)
+		}
+
+		datastore := New()
+
+		resp, err := datastore.Patch(ctx, v.id, v.patch, v.version)
+
+		if !reflect.DeepEqual(resp, v.resp) {
+			t.Errorf("desc : %v ,[TEST%d]Failed. Got %v\tExpected %v\n", v.desc, i+1, resp, v.resp)
+		}
+
+		if !reflect.DeepEqual(err, v.err) {
+			t.Errorf("desc : %v ,[TEST%d]Failed. Got %v\tExpected %v\n", v.desc, i+1, err, v.err)
 		}
 	}
 }
@@ -238,13 +577,19 @@ func Test_Delete(t *testing.T) {
 	testcases := []struct {
 		desc        string
 		id          int
+		version     int
 		rowAffected int
 		result      driver.Result
+		execErr     error
 		err         error
 	}{
 		{desc: "valid", id: 1, rowAffected: 1, result: sqlmock.NewResult(1, 1)},
-		{desc: "error in exec", id: 1, rowAffected: 0, result: sqlmock.NewResult(0, 0), err: errors.New("error in exec")},
-		{desc: "error in rowAffected", id: 11, result: sqlmock.NewErrorResult(errors.New("sql: no rows in result set"))},
+		{desc: "error in exec", id: 1, rowAffected: 0, result: sqlmock.NewResult(0, 0),
+			execErr: errors.New("error in exec"), err: errors.New("error in exec")},
+		{desc: "error in rowAffected", id: 11, result: sqlmock.NewErrorResult(errors.New("error in rowAffected")),
+			err: errors.New("error in rowAffected")},
+		{desc: "at the version", id: 5, version: 2, rowAffected: 1, result: sqlmock.NewResult(0, 1)},
+		{desc: "version moved on", id: 6, version: 2, result: sqlmock.NewResult(0, 0)},
 	}
 
 	// Customize SQL query matching
@@ -263,13 +608,17 @@ func Test_Delete(t *testing.T) {
 
 	for i, v := range testcases {
 		// Mocking delete query from book
-		mock.ExpectExec("DELETE FROM Book where bookId=?").WithArgs(v.id).
-			WillReturnResult(v.result).WillReturnError(v.err)
+		query, args := "DELETE FROM Book where bookId=?", []driver.Value{v.id}
+		if v.version > 0 {
+			query, args = query+" AND version=?", append(args, v.version)
+		}
+
+		mock.ExpectExec(query).WithArgs(args...).WillReturnResult(v.result).WillReturnError(v.execErr)
 
 		// Injecting mock DB
 		datastore := New()
 
-		resp, err := datastore.Delete(ctx, v.id)
+		resp, err := datastore.Delete(ctx, v.id, v.version)
 
 		// Comparing body
 		if !reflect.DeepEqual(resp, v.rowAffected) {
@@ -277,34 +626,190 @@ func Test_Delete(t *testing.T) {
 		}
 
 		// Comparing errors
+		if !reflect.DeepEqual(err, v.err) {
+			t.Errorf("desc : %v ,[TEST%d]Failed. Got %v\tExpected %v\n", v.desc, i+1, err, v.err)
+		}
+	}
+}
+
+// Test_DeleteByAuthorID Testing deleting the books of an author
+func Test_DeleteByAuthorID(t *testing.T) {
+	testcases := []struct {
+		desc        string
+		authorID    int
+		rowAffected int
+		result      driver.Result
+		err         error
+	}{
+		{desc: "valid", authorID: 1, rowAffected: 2, result: sqlmock.NewResult(0, 2)},
+		{desc: "error in exec", authorID: 2, result: sqlmock.NewResult(0, 0), err: errors.New("error in exec")},
+		{desc: "error in rowAffected", authorID: 3, result: sqlmock.NewErrorResult(errors.New("error in rowAffected"))},
+	}
+
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	if err != nil {
+		log.Printf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+
+	app := gofr.New()
+	app.DB().DB = db
+
+	ctx := gofr.NewContext(nil, nil, app)
+
+	defer db.Close()
+
+	for i, v := range testcases {
+		mock.ExpectExec("DELETE FROM Book where authorId=?").WithArgs(v.authorID).
+			WillReturnResult(v.result).WillReturnError(v.err)
+
+		datastore := New()
+
+		resp, err := datastore.DeleteByAuthorID(ctx, v.authorID)
+
+		if !reflect.DeepEqual(resp, v.rowAffected) {
+			t.Errorf("desc : %v ,[TEST%d]Failed. Got %v\tExpected %v\n", v.desc, i+1, resp, v.rowAffected)
+		}
+
 		if err != nil {
 			log.Printf("desc : %v ,[TEST%d]Failed. Got %v\tExpected %v\n", v.desc, i+1, err, v.err)
 		}
 	}
 }
 
-// Test_GetByTitle Testing book Get by id
-func Test_GetByTitle(t *testing.T) {
+// Test_ReassignAuthor Testing moving the books of an author to another author
+func Test_ReassignAuthor(t *testing.T) {
 	testcases := []struct {
-		desc  string
-		title string
-		resp  []models.Book
-		rows  *sqlmock.Rows
-		err   error
+		desc        string
+		from        int
+		to          int
+		rowAffected int
+		result      driver.Result
+		err         error
+	}{
+		{desc: "valid", from: 1, to: 2, rowAffected: 3, result: sqlmock.NewResult(0, 3)},
+		{desc: "error in exec", from: 1, to: 3, result: sqlmock.NewResult(0, 0), err: errors.New("error in exec")},
+		{desc: "error in rowAffected", from: 1, to: 4, result: sqlmock.NewErrorResult(errors.New("error in rowAffected"))},
+	}
+
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	if err != nil {
+		log.Printf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+
+	app := gofr.New()
+	app.DB().DB = db
+
+	ctx := gofr.NewContext(nil, nil, app)
+
+	defer db.Close()
+
+	for i, v := range testcases {
+		mock.ExpectExec("UPDATE Book SET authorId=?, version=version+1 where authorId=?").WithArgs(v.to, v.from).
+			WillReturnResult(v.result).WillReturnError(v.err)
+
+		datastore := New()
+
+		resp, err := datastore.ReassignAuthor(ctx, v.from, v.to)
+
+		if !reflect.DeepEqual(resp, v.rowAffected) {
+			t.Errorf("desc : %v ,[TEST%d]Failed. Got %v\tExpected %v\n", v.desc, i+1, resp, v.rowAffected)
+		}
+
+		if err != nil {
+			log.Printf("desc : %v ,[TEST%d]Failed. Got %v\tExpected %v\n", v.desc, i+1, err, v.err)
+		}
+	}
+}
+
+// Test_TouchPublication Testing bumping the version of the books of a publication
+func Test_TouchPublication(t *testing.T) {
+	testcases := []struct {
+		desc        string
+		publication string
+		rowAffected int
+		result      driver.Result
+		err         error
 	}{
-		{desc: "valid details ", title: "States", resp: []models.Book{
-			{BookID: 1, AuthorID: 1,
-				Title: "States", Publication: "Scholastic", PublishedDate: "16/03/2016"},
-			{BookID: 2, AuthorID: 1,
-				Title: "States", Publication: "Penguin", PublishedDate: "11/03/2016"}},
-			rows: sqlmock.NewRows([]string{"bookId", "title", "authorId", "Publication", "PublishedDate"}).
-				AddRow(1, "States", 1, "Scholastic", "16/03/2016").
-				AddRow(2, "States", 1, "Penguin", "11/03/2016"),
+		{desc: "valid", publication: "Penguin", rowAffected: 3, result: sqlmock.NewResult(0, 3)},
+		{desc: "error in exec", publication: "Arihant", result: sqlmock.NewResult(0, 0),
+			err: errors.New("error in exec")},
+		{desc: "error in rowAffected", publication: "Scholastic",
+			result: sqlmock.NewErrorResult(errors.New("error in rowAffected"))},
+	}
+
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	if err != nil {
+		log.Printf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+
+	app := gofr.New()
+	app.DB().DB = db
+
+	ctx := gofr.NewContext(nil, nil, app)
+
+	defer db.Close()
+
+	for i, v := range testcases {
+		mock.ExpectExec("UPDATE Book SET version=version+1 where Publication=?").WithArgs(v.publication).
+			WillReturnResult(v.result).WillReturnError(v.err)
+
+		datastore := New()
+
+		resp, err := datastore.TouchPublication(ctx, v.publication)
+
+		if !reflect.DeepEqual(resp, v.rowAffected) {
+			t.Errorf("desc : %v ,[TEST%d]Failed. Got %v\tExpected %v\n", v.desc, i+1, resp, v.rowAffected)
+		}
+
+		if err != nil {
+			log.Printf("desc : %v ,[TEST%d]Failed. Got %v\tExpected %v\n", v.desc, i+1, err, v.err)
+		}
+	}
+}
+
+// Test_GetAllFilter Testing book listing with filters
+func Test_GetAllFilter(t *testing.T) {
+	testcases := []struct {
+		desc   string
+		filter models.BookFilter
+		query  string
+		args   []driver.Value
+		resp   []models.Book
+		rows   *sqlmock.Rows
+		err    error
+	}{
+		{desc: "exact title", filter: models.BookFilter{Title: "States"},
+			query: "SELECT bookId, title, authorId, Publication, PublishedDate, isbn, version FROM Book WHERE title=? ORDER BY bookId LIMIT ? OFFSET ?",
+			args:  []driver.Value{"States", 21, 0},
+			resp: []models.Book{{BookID: 1, AuthorID: 1, Title: "States", Publication: "Scholastic",
+				PublishedDate: models.NewDate(2016, 3, 16), Version: 1}},
+			rows: sqlmock.NewRows([]string{"bookId", "title", "authorId", "Publication", "PublishedDate", "isbn", "version"}).
+				AddRow(1, "States", 1, "Scholastic", "2016-03-16", nil, 1),
+		},
+		{desc: "title prefix", filter: models.BookFilter{Title: "50%_", TitleMatch: models.MatchPrefix},
+			query: "SELECT bookId, title, authorId, Publication, PublishedDate, isbn, version FROM Book WHERE title LIKE ? ORDER BY bookId LIMIT ? OFFSET ?",
+			args:  []driver.Value{`50\%\_%`, 21, 0}, rows: sqlmock.NewRows([]string{"bookId", "title", "authorId",
+				"Publication", "PublishedDate", "isbn", "version"}),
 		},
-		{desc: "error in scanning", title: "States", resp: []models.Book{}, rows: sqlmock.NewRows([]string{"bookId", "title",
-			"authorId", "Publication", "PublishedDate"}).AddRow("abc", "States", 1, "Scholastic", "16/03/2016"),
+		{desc: "title substring", filter: models.BookFilter{Title: "States", TitleMatch: models.MatchContains},
+			query: "SELECT bookId, title, authorId, Publication, PublishedDate, isbn, version FROM Book WHERE title LIKE ? ORDER BY bookId LIMIT ? OFFSET ?",
+			args:  []driver.Value{"%States%", 21, 0}, rows: sqlmock.NewRows([]string{"bookId", "title", "authorId",
+				"Publication", "PublishedDate", "isbn", "version"}),
+		},
+		{desc: "all filters", filter: models.BookFilter{Title: "States", AuthorID: 1, Publication: "Penguin",
+			PublishedAfter: models.NewDate(2010, 1, 1), PublishedBefore: models.NewDate(2020, 12, 31)},
+			query: "SELECT bookId, title, authorId, Publication, PublishedDate, isbn, version FROM Book WHERE title=? AND authorId=? AND Publication=? AND " +
+				"PublishedDate>=? AND PublishedDate<=? ORDER BY bookId LIMIT ? OFFSET ?",
+			args: []driver.Value{"States", 1, "Penguin", "2010-01-01", "2020-12-31", 21, 0},
+			resp: []models.Book{{BookID: 2, AuthorID: 1, Title: "States", Publication: "Penguin",
+				PublishedDate: models.NewDate(2016, 3, 11), Version: 1}},
+			rows: sqlmock.NewRows([]string{"bookId", "title", "authorId", "Publication", "PublishedDate", "isbn", "version"}).
+				AddRow(2, "States", 1, "Penguin", "2016-03-11", nil, 1),
+		},
+		{desc: "error in select", filter: models.BookFilter{AuthorID: 3},
+			query: "SELECT bookId, title, authorId, Publication, PublishedDate, isbn, version FROM Book WHERE authorId=? ORDER BY bookId LIMIT ? OFFSET ?",
+			args:  []driver.Value{3, 21, 0}, rows: sqlmock.NewRows([]string{}), err: errors.New("error in select"),
 		},
-		{desc: "error in select all ", resp: []models.Book{}, rows: sqlmock.NewRows([]string{}), err: errors.New("error in select all by title")},
 	}
 
 	// Customize SQL query matching
@@ -322,28 +827,76 @@ func Test_GetByTitle(t *testing.T) {
 	defer db.Close()
 
 	for i, v := range testcases {
-		// Mocking select all books query
-		mock.ExpectQuery("select * from Book where title=?").WithArgs(v.title).
-			WillReturnRows(v.rows).WillReturnError(v.err)
+		// Mocking filtered select query
+		mock.ExpectQuery(v.query).WithArgs(v.args...).WillReturnRows(v.rows).WillReturnError(v.err)
 
 		// injecting mock db
 		datastore := New()
 
-		resp, err := datastore.GetBookByTitle(ctx, v.title)
+		resp, err := datastore.GetAll(ctx, v.filter, nil, models.Page{Limit: 21})
 
 		// Comparing body
 		if !reflect.DeepEqual(resp, v.resp) {
 			t.Errorf("Desc : %v,[TEST%d]Failed. Got %v\tExpected %v\n", v.desc, i+1, resp, v.resp)
 		}
 
-		if err != nil {
-			log.Printf("desc : %v ,[TEST%d] Got %v\tExpected %v\n", v.desc, i+1, err, v.err)
+		if !reflect.DeepEqual(err, v.err) {
+			t.Errorf("Desc : %v,[TEST%d]Failed. Got %v\tExpected %v\n", v.desc, i+1, err, v.err)
 		}
 	}
 }
 
-// Test_IsBookPresent is to check for book existence
-func Test_IsBookPresent(t *testing.T) {
+// Test_Count is to test counting of books
+func Test_Count(t *testing.T) {
+	testcases := []struct {
+		desc   string
+		filter models.BookFilter
+		query  string
+		args   []driver.Value
+		rows   *sqlmock.Rows
+		resp   int
+		err    error
+	}{
+		{desc: "all books", query: "SELECT count(*) FROM Book", rows: sqlmock.NewRows([]string{"count(*)"}).AddRow(5),
+			resp: 5},
+		{desc: "books by title and author", filter: models.BookFilter{Title: "States", AuthorID: 1},
+			query: "SELECT count(*) FROM Book WHERE title=? AND authorId=?", args: []driver.Value{"States", 1},
+			rows: sqlmock.NewRows([]string{"count(*)"}).AddRow(2), resp: 2},
+		{desc: "error in count", query: "SELECT count(*) FROM Book", rows: sqlmock.NewRows([]string{}),
+			err: errors.New("error in count")},
+	}
+
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	if err != nil {
+		log.Printf("error mocking:%v", err)
+	}
+
+	app := gofr.New()
+	app.DB().DB = db
+
+	ctx := gofr.NewContext(nil, nil, app)
+
+	defer db.Close()
+
+	for i, v := range testcases {
+		mock.ExpectQuery(v.query).WithArgs(v.args...).WillReturnRows(v.rows).WillReturnError(v.err)
+
+		datastore := New()
+
+		resp, err := datastore.Count(ctx, v.filter)
+
+		if resp != v.resp {
+			t.Errorf("desc : %v ,[TEST%d]Failed. Got %v\tExpected %v\n", v.desc, i+1, resp, v.resp)
+		}
+
+		if !reflect.DeepEqual(err, v.err) {
+			t.Errorf("desc : %v ,[TEST%d]Failed. Got %v\tExpected %v\n", v.desc, i+1, err, v.err)
+		}
+	}
+}
+
+// Test_Exists is to check for book existence, a failure of the DB is not a missing book
+func Test_Exists(t *testing.T) {
 	testCases := []struct {
 		desc string
 		id   int
@@ -351,9 +904,9 @@ func Test_IsBookPresent(t *testing.T) {
 		resp bool
 		err  error
 	}{
-		{desc: "valid", id: 1, resp: false, rows: sqlmock.NewRows([]string{"bookId", "title", "authorId", "Publication", "PublishedDate"}).
-			AddRow(1, "States", 1, "Scholastic", "16/03/2016")},
-		{desc: "error in scanning", id: 10, rows: sqlmock.NewRows([]string{}), resp: true},
+		{desc: "exists", id: 1, resp: true, rows: sqlmock.NewRows([]string{"1"}).AddRow(1)},
+		{desc: "id not exist", id: 10, rows: sqlmock.NewRows([]string{"1"})},
+		{desc: "error in query", id: 11, rows: sqlmock.NewRows([]string{"1"}), err: errors.New("connection refused")},
 	}
 
 	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
@@ -369,13 +922,19 @@ func Test_IsBookPresent(t *testing.T) {
 	defer db.Close()
 
 	for i, v := range testCases {
-		mock.ExpectQuery("select * from Book where bookId=?").WillReturnRows(v.rows).WillReturnError(v.err)
+		mock.ExpectQuery("SELECT 1 FROM Book WHERE bookId=? LIMIT 1").WithArgs(v.id).WillReturnRows(v.rows).
+			WillReturnError(v.err)
+
 		datastore := New()
 
-		resp := datastore.IsBookPresent(ctx, v.id)
+		resp, err := datastore.Exists(ctx, v.id)
 
 		if !reflect.DeepEqual(resp, v.resp) {
 			t.Errorf("desc : %v ,[TEST%d]Failed. Got %v\tExpected %v\n", v.desc, i+1, resp, v.resp)
 		}
+
+		if !reflect.DeepEqual(err, v.err) {
+			t.Errorf("desc : %v ,[TEST%d]Failed. Got %v\tExpected %v\n", v.desc, i+1, err, v.err)
+		}
 	}
 }
@@ -0,0 +1,121 @@
+package book
+
+import (
+	"strings"
+
+	"mytest/models"
+)
+
+// sortColumns is the whitelist of the fields a book listing can be ordered by, along with their columns
+var sortColumns = map[string]string{
+	"bookId":        "bookId",
+	"title":         "title",
+	"authorId":      "authorId",
+	"publication":   "Publication",
+	"publishedDate": "PublishedDate",
+}
+
+// query is a builder of parameterized WHERE clauses
+type query struct {
+	conditions []string
+	args       []interface{}
+}
+
+// where adds a condition along with its arguments
+func (q *query) where(condition string, args ...interface{}) {
+	q.conditions = append(q.conditions, condition)
+	q.args = append(q.args, args...)
+}
+
+// clause returns the WHERE clause joining all the conditions with AND
+func (q *query) clause() string {
+	if len(q.conditions) == 0 {
+		return ""
+	}
+
+	return " WHERE " + strings.Join(q.conditions, " AND ")
+}
+
+// after adds the keyset condition selecting the rows which come after the given sort key values
+func (q *query) after(sort []models.SortField, values []string) {
+	var (
+		or   []string
+		args []interface{}
+	)
+
+	for i, s := range sort {
+		var and []string
+
+		for j := 0; j < i; j++ {
+			and = append(and, sortColumns[sort[j].Field]+"=?")
+			args = append(args, values[j])
+		}
+
+		op := ">"
+		if s.Desc {
+			op = "<"
+		}
+
+		and = append(and, sortColumns[s.Field]+op+"?")
+		args = append(args, values[i])
+
+		or = append(or, "("+strings.Join(and, " AND ")+")")
+	}
+
+	q.where("("+strings.Join(or, " OR ")+")", args...)
+}
+
+// orderBy is to build the ORDER BY clause of the given sort
+func orderBy(sort []models.SortField) string {
+	columns := make([]string, 0, len(sort))
+
+	for _, s := range sort {
+		column := sortColumns[s.Field]
+		if s.Desc {
+			column += " DESC"
+		}
+
+		columns = append(columns, column)
+	}
+
+	return " ORDER BY " + strings.Join(columns, ",")
+}
+
+// filterQuery is to build the query matching the given filter
+func filterQuery(filter models.BookFilter) *query {
+	q := &query{}
+
+	if filter.Title != "" {
+		switch filter.TitleMatch {
+		case models.MatchPrefix:
+			q.where("title LIKE ?", escapeLike(filter.Title)+"%")
+		case models.MatchContains:
+			q.where("title LIKE ?", "%"+escapeLike(filter.Title)+"%")
+		default:
+			q.where("title=?", filter.Title)
+		}
+	}
+
+	if filter.AuthorID != 0 {
+		q.where("authorId=?", filter.AuthorID)
+	}
+
+	if filter.Publication != "" {
+		q.where("Publication=?", filter.Publication)
+	}
+
+	if !filter.PublishedAfter.IsZero() {
+		q.where("PublishedDate>=?", filter.PublishedAfter)
+	}
+
+	if !filter.PublishedBefore.IsZero() {
+		q.where("PublishedDate<=?", filter.PublishedBefore)
+	}
+
+	return q
+}
+
+// escapeLike is to escape the wildcards of a LIKE pattern
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(s)
+}
@@ -0,0 +1,25 @@
+package datastore
+
+import (
+	stdErrors "errors"
+
+	"github.com/go-sql-driver/mysql"
+
+	"mytest/models/errors"
+)
+
+// errDuplicateKey is the number of the MySQL error a statement clashing with a unique key fails with
+const errDuplicateKey = 1062
+
+// Conflict method is to map the error of a statement clashing with a unique key to a Conflict of the given
+// code, which is what a concurrent request passing the same check as the statement ends with. The clashing
+// row is not known so there is no ResourceID, any other error is returned as it is.
+func Conflict(err error, code, reason string) error {
+	var mysqlErr *mysql.MySQLError
+
+	if stdErrors.As(err, &mysqlErr) && mysqlErr.Number == errDuplicateKey {
+		return errors.Conflict{Code: code, Reason: reason}
+	}
+
+	return err
+}
@@ -0,0 +1,39 @@
+package datastore
+
+import (
+	stdErrors "errors"
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/go-sql-driver/mysql"
+
+	"mytest/models/errors"
+)
+
+// TestConflict function is to test that only a duplicate key error is mapped to a Conflict
+func TestConflict(t *testing.T) {
+	errDB := stdErrors.New("error in DB")
+	conflict := errors.Conflict{Code: errors.CodeBookExists, Reason: "book already exists"}
+
+	testcases := []struct {
+		desc string
+		err  error
+		resp error
+	}{
+		{desc: "duplicate key", err: &mysql.MySQLError{Number: 1062, Message: "Duplicate entry"}, resp: conflict},
+		{desc: "wrapped duplicate key", err: fmt.Errorf("insert: %w", &mysql.MySQLError{Number: 1062}),
+			resp: conflict},
+		{desc: "other mysql error", err: &mysql.MySQLError{Number: 1452}, resp: &mysql.MySQLError{Number: 1452}},
+		{desc: "other error", err: errDB, resp: errDB},
+		{desc: "no error", err: nil, resp: nil},
+	}
+
+	for i, v := range testcases {
+		resp := Conflict(v.err, errors.CodeBookExists, "book already exists")
+
+		if !reflect.DeepEqual(resp, v.resp) {
+			t.Errorf("desc : %v ,[TEST%d]Failed. Got %v\tExpected %v\n", v.desc, i+1, resp, v.resp)
+		}
+	}
+}
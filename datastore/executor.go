@@ -0,0 +1,13 @@
+package datastore
+
+import (
+	"database/sql"
+)
+
+// Executor is implemented by both the DB of the context and *sql.Tx, so that a datastore runs its statements
+// on either of them
+type Executor interface {
+	Exec(query string, args ...interface{}) (sql.Result, error)
+	Query(query string, args ...interface{}) (*sql.Rows, error)
+	QueryRow(query string, args ...interface{}) *sql.Row
+}
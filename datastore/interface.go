@@ -7,18 +7,53 @@ import (
 
 type Book interface {
 	Post(c *gofr.Context, book *models.Book) (models.Book, error)
-	GetAll(c *gofr.Context) ([]models.Book, error)
+	GetAll(c *gofr.Context, filter models.BookFilter, sort []models.SortField, page models.Page) ([]models.Book, error)
 	GetByID(c *gofr.Context, id int) (models.Book, error)
-	Update(c *gofr.Context, id int, book *models.Book) (models.Book, error)
-	Delete(c *gofr.Context, id int) (int, error)
-	GetBookByTitle(c *gofr.Context, title string) ([]models.Book, error)
-	IsBookPresent(c *gofr.Context, id int) bool
+	GetByISBN(c *gofr.Context, isbn models.ISBN) (models.Book, error)
+	GetDuplicate(c *gofr.Context, id int, book *models.Book, anyISBN bool) (models.Book, error)
+	Update(c *gofr.Context, id int, book *models.Book, version int) (models.Book, error)
+	Patch(c *gofr.Context, id int, patch models.BookPatch, version int) (models.Book, error)
+	Delete(c *gofr.Context, id, version int) (int, error)
+	Count(c *gofr.Context, filter models.BookFilter) (int, error)
+	DeleteByAuthorID(c *gofr.Context, authorID int) (int, error)
+	ReassignAuthor(c *gofr.Context, from, to int) (int, error)
+	TouchPublication(c *gofr.Context, publication string) (int, error)
+	Exists(c *gofr.Context, id int) (bool, error)
 }
 
 type Author interface {
 	Post(c *gofr.Context, auth models.Author) (models.Author, error)
-	Update(c *gofr.Context, id int, author models.Author) (models.Author, error)
-	Delete(c *gofr.Context, id int) (int, error)
+	GetAll(c *gofr.Context) ([]models.Author, error)
+	GetByID(c *gofr.Context, id int) (models.Author, error)
+	GetByPenName(c *gofr.Context, penName string) (models.Author, error)
+	Update(c *gofr.Context, id int, author models.Author, version int) (models.Author, error)
+	Patch(c *gofr.Context, id int, patch models.AuthorPatch, version int) (models.Author, error)
+	Delete(c *gofr.Context, id, version int) (int, error)
 	IncludeAuthor(c *gofr.Context, id int) (models.Author, error)
-	IsAuthorIDPresent(c *gofr.Context, id int) bool
+	IncludeAuthors(c *gofr.Context, ids []int) (map[int]models.Author, error)
+	Lock(c *gofr.Context, id int) (models.Author, error)
+	LockForUpdate(c *gofr.Context, id int) (models.Author, error)
+	Exists(c *gofr.Context, id int) (bool, error)
+}
+
+type Publisher interface {
+	Post(c *gofr.Context, publisher models.Publisher) (models.Publisher, error)
+	GetAll(c *gofr.Context) ([]models.Publisher, error)
+	GetByID(c *gofr.Context, id int) (models.Publisher, error)
+	GetByName(c *gofr.Context, name string) (models.Publisher, error)
+	LockForUpdate(c *gofr.Context, id int) (models.Publisher, error)
+	Update(c *gofr.Context, id int, publisher models.Publisher) (models.Publisher, error)
+	Delete(c *gofr.Context, id int) (int, error)
+}
+
+// Tx is the datastores running their statements in the transaction of a UnitOfWork
+type Tx interface {
+	Book() Book
+	Author() Author
+	Publisher() Publisher
+}
+
+// UnitOfWork runs the work in one DB transaction, committed when the work returns nil and rolled back otherwise
+type UnitOfWork interface {
+	Do(c *gofr.Context, work func(tx Tx) error) error
 }
@@ -35,49 +35,79 @@ func (m *MockBook) EXPECT() *MockBookMockRecorder {
 	return m.recorder
 }
 
+// Count mocks base method.
+func (m *MockBook) Count(c *gofr.Context, filter models.BookFilter) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Count", c, filter)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Count indicates an expected call of Count.
+func (mr *MockBookMockRecorder) Count(c, filter interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Count", reflect.TypeOf((*MockBook)(nil).Count), c, filter)
+}
+
 // Delete mocks base method.
-func (m *MockBook) Delete(c *gofr.Context, id int) (int, error) {
+func (m *MockBook) Delete(c *gofr.Context, id, version int) (int, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Delete", c, id)
+	ret := m.ctrl.Call(m, "Delete", c, id, version)
 	ret0, _ := ret[0].(int)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // Delete indicates an expected call of Delete.
-func (mr *MockBookMockRecorder) Delete(c, id interface{}) *gomock.Call {
+func (mr *MockBookMockRecorder) Delete(c, id, version interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockBook)(nil).Delete), c, id)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockBook)(nil).Delete), c, id, version)
 }
 
-// GetAll mocks base method.
-func (m *MockBook) GetAll(c *gofr.Context) ([]models.Book, error) {
+// DeleteByAuthorID mocks base method.
+func (m *MockBook) DeleteByAuthorID(c *gofr.Context, authorID int) (int, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetAll", c)
-	ret0, _ := ret[0].([]models.Book)
+	ret := m.ctrl.Call(m, "DeleteByAuthorID", c, authorID)
+	ret0, _ := ret[0].(int)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetAll indicates an expected call of GetAll.
-func (mr *MockBookMockRecorder) GetAll(c interface{}) *gomock.Call {
+// DeleteByAuthorID indicates an expected call of DeleteByAuthorID.
+func (mr *MockBookMockRecorder) DeleteByAuthorID(c, authorID interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAll", reflect.TypeOf((*MockBook)(nil).GetAll), c)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteByAuthorID", reflect.TypeOf((*MockBook)(nil).DeleteByAuthorID), c, authorID)
 }
 
-// GetBookByTitle mocks base method.
-func (m *MockBook) GetBookByTitle(c *gofr.Context, title string) ([]models.Book, error) {
+// Exists mocks base method.
+func (m *MockBook) Exists(c *gofr.Context, id int) (bool, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetBookByTitle", c, title)
+	ret := m.ctrl.Call(m, "Exists", c, id)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Exists indicates an expected call of Exists.
+func (mr *MockBookMockRecorder) Exists(c, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Exists", reflect.TypeOf((*MockBook)(nil).Exists), c, id)
+}
+
+// GetAll mocks base method.
+func (m *MockBook) GetAll(c *gofr.Context, filter models.BookFilter, sort []models.SortField, page models.Page) ([]models.Book, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAll", c, filter, sort, page)
 	ret0, _ := ret[0].([]models.Book)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetBookByTitle indicates an expected call of GetBookByTitle.
-func (mr *MockBookMockRecorder) GetBookByTitle(c, title interface{}) *gomock.Call {
+// GetAll indicates an expected call of GetAll.
+func (mr *MockBookMockRecorder) GetAll(c, filter, sort, page interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBookByTitle", reflect.TypeOf((*MockBook)(nil).GetBookByTitle), c, title)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAll", reflect.TypeOf((*MockBook)(nil).GetAll), c, filter, sort, page)
 }
 
 // GetByID mocks base method.
@@ -95,18 +125,49 @@ func (mr *MockBookMockRecorder) GetByID(c, id interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByID", reflect.TypeOf((*MockBook)(nil).GetByID), c, id)
 }
 
-// IsBookPresent mocks base method.
-func (m *MockBook) IsBookPresent(c *gofr.Context, id int) bool {
+// GetByISBN mocks base method.
+func (m *MockBook) GetByISBN(c *gofr.Context, isbn models.ISBN) (models.Book, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "IsBookPresent", c, id)
-	ret0, _ := ret[0].(bool)
-	return ret0
+	ret := m.ctrl.Call(m, "GetByISBN", c, isbn)
+	ret0, _ := ret[0].(models.Book)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
 }
 
-// IsBookPresent indicates an expected call of IsBookPresent.
-func (mr *MockBookMockRecorder) IsBookPresent(c, id interface{}) *gomock.Call {
+// GetByISBN indicates an expected call of GetByISBN.
+func (mr *MockBookMockRecorder) GetByISBN(c, isbn interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsBookPresent", reflect.TypeOf((*MockBook)(nil).IsBookPresent), c, id)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByISBN", reflect.TypeOf((*MockBook)(nil).GetByISBN), c, isbn)
+}
+
+// GetDuplicate mocks base method.
+func (m *MockBook) GetDuplicate(c *gofr.Context, id int, book *models.Book, anyISBN bool) (models.Book, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetDuplicate", c, id, book, anyISBN)
+	ret0, _ := ret[0].(models.Book)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetDuplicate indicates an expected call of GetDuplicate.
+func (mr *MockBookMockRecorder) GetDuplicate(c, id, book, anyISBN interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDuplicate", reflect.TypeOf((*MockBook)(nil).GetDuplicate), c, id, book, anyISBN)
+}
+
+// Patch mocks base method.
+func (m *MockBook) Patch(c *gofr.Context, id int, patch models.BookPatch, version int) (models.Book, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Patch", c, id, patch, version)
+	ret0, _ := ret[0].(models.Book)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Patch indicates an expected call of Patch.
+func (mr *MockBookMockRecorder) Patch(c, id, patch, version interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Patch", reflect.TypeOf((*MockBook)(nil).Patch), c, id, patch, version)
 }
 
 // Post mocks base method.
@@ -124,19 +185,49 @@ func (mr *MockBookMockRecorder) Post(c, book interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Post", reflect.TypeOf((*MockBook)(nil).Post), c, book)
 }
 
+// ReassignAuthor mocks base method.
+func (m *MockBook) ReassignAuthor(c *gofr.Context, from, to int) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReassignAuthor", c, from, to)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ReassignAuthor indicates an expected call of ReassignAuthor.
+func (mr *MockBookMockRecorder) ReassignAuthor(c, from, to interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReassignAuthor", reflect.TypeOf((*MockBook)(nil).ReassignAuthor), c, from, to)
+}
+
+// TouchPublication mocks base method.
+func (m *MockBook) TouchPublication(c *gofr.Context, publication string) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "TouchPublication", c, publication)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// TouchPublication indicates an expected call of TouchPublication.
+func (mr *MockBookMockRecorder) TouchPublication(c, publication interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TouchPublication", reflect.TypeOf((*MockBook)(nil).TouchPublication), c, publication)
+}
+
 // Update mocks base method.
-func (m *MockBook) Update(c *gofr.Context, id int, book *models.Book) (models.Book, error) {
+func (m *MockBook) Update(c *gofr.Context, id int, book *models.Book, version int) (models.Book, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Update", c, id, book)
+	ret := m.ctrl.Call(m, "Update", c, id, book, version)
 	ret0, _ := ret[0].(models.Book)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // Update indicates an expected call of Update.
-func (mr *MockBookMockRecorder) Update(c, id, book interface{}) *gomock.Call {
+func (mr *MockBookMockRecorder) Update(c, id, book, version interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockBook)(nil).Update), c, id, book)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockBook)(nil).Update), c, id, book, version)
 }
 
 // MockAuthor is a mock of Author interface.
@@ -163,18 +254,78 @@ func (m *MockAuthor) EXPECT() *MockAuthorMockRecorder {
 }
 
 // Delete mocks base method.
-func (m *MockAuthor) Delete(c *gofr.Context, id int) (int, error) {
+func (m *MockAuthor) Delete(c *gofr.Context, id, version int) (int, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Delete", c, id)
+	ret := m.ctrl.Call(m, "Delete", c, id, version)
 	ret0, _ := ret[0].(int)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // Delete indicates an expected call of Delete.
-func (mr *MockAuthorMockRecorder) Delete(c, id interface{}) *gomock.Call {
+func (mr *MockAuthorMockRecorder) Delete(c, id, version interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockAuthor)(nil).Delete), c, id)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockAuthor)(nil).Delete), c, id, version)
+}
+
+// Exists mocks base method.
+func (m *MockAuthor) Exists(c *gofr.Context, id int) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Exists", c, id)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Exists indicates an expected call of Exists.
+func (mr *MockAuthorMockRecorder) Exists(c, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Exists", reflect.TypeOf((*MockAuthor)(nil).Exists), c, id)
+}
+
+// GetAll mocks base method.
+func (m *MockAuthor) GetAll(c *gofr.Context) ([]models.Author, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAll", c)
+	ret0, _ := ret[0].([]models.Author)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAll indicates an expected call of GetAll.
+func (mr *MockAuthorMockRecorder) GetAll(c interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAll", reflect.TypeOf((*MockAuthor)(nil).GetAll), c)
+}
+
+// GetByID mocks base method.
+func (m *MockAuthor) GetByID(c *gofr.Context, id int) (models.Author, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByID", c, id)
+	ret0, _ := ret[0].(models.Author)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByID indicates an expected call of GetByID.
+func (mr *MockAuthorMockRecorder) GetByID(c, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByID", reflect.TypeOf((*MockAuthor)(nil).GetByID), c, id)
+}
+
+// GetByPenName mocks base method.
+func (m *MockAuthor) GetByPenName(c *gofr.Context, penName string) (models.Author, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByPenName", c, penName)
+	ret0, _ := ret[0].(models.Author)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByPenName indicates an expected call of GetByPenName.
+func (mr *MockAuthorMockRecorder) GetByPenName(c, penName interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByPenName", reflect.TypeOf((*MockAuthor)(nil).GetByPenName), c, penName)
 }
 
 // IncludeAuthor mocks base method.
@@ -192,18 +343,64 @@ func (mr *MockAuthorMockRecorder) IncludeAuthor(c, id interface{}) *gomock.Call
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IncludeAuthor", reflect.TypeOf((*MockAuthor)(nil).IncludeAuthor), c, id)
 }
 
-// IsAuthorIDPresent mocks base method.
-func (m *MockAuthor) IsAuthorIDPresent(c *gofr.Context, id int) bool {
+// IncludeAuthors mocks base method.
+func (m *MockAuthor) IncludeAuthors(c *gofr.Context, ids []int) (map[int]models.Author, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "IsAuthorIDPresent", c, id)
-	ret0, _ := ret[0].(bool)
-	return ret0
+	ret := m.ctrl.Call(m, "IncludeAuthors", c, ids)
+	ret0, _ := ret[0].(map[int]models.Author)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// IncludeAuthors indicates an expected call of IncludeAuthors.
+func (mr *MockAuthorMockRecorder) IncludeAuthors(c, ids interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IncludeAuthors", reflect.TypeOf((*MockAuthor)(nil).IncludeAuthors), c, ids)
+}
+
+// Lock mocks base method.
+func (m *MockAuthor) Lock(c *gofr.Context, id int) (models.Author, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Lock", c, id)
+	ret0, _ := ret[0].(models.Author)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Lock indicates an expected call of Lock.
+func (mr *MockAuthorMockRecorder) Lock(c, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Lock", reflect.TypeOf((*MockAuthor)(nil).Lock), c, id)
+}
+
+// LockForUpdate mocks base method.
+func (m *MockAuthor) LockForUpdate(c *gofr.Context, id int) (models.Author, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LockForUpdate", c, id)
+	ret0, _ := ret[0].(models.Author)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// LockForUpdate indicates an expected call of LockForUpdate.
+func (mr *MockAuthorMockRecorder) LockForUpdate(c, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LockForUpdate", reflect.TypeOf((*MockAuthor)(nil).LockForUpdate), c, id)
+}
+
+// Patch mocks base method.
+func (m *MockAuthor) Patch(c *gofr.Context, id int, patch models.AuthorPatch, version int) (models.Author, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Patch", c, id, patch, version)
+	ret0, _ := ret[0].(models.Author)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
 }
 
-// IsAuthorIDPresent indicates an expected call of IsAuthorIDPresent.
-func (mr *MockAuthorMockRecorder) IsAuthorIDPresent(c, id interface{}) *gomock.Call {
+// Patch indicates an expected call of Patch.
+func (mr *MockAuthorMockRecorder) Patch(c, id, patch, version interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsAuthorIDPresent", reflect.TypeOf((*MockAuthor)(nil).IsAuthorIDPresent), c, id)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Patch", reflect.TypeOf((*MockAuthor)(nil).Patch), c, id, patch, version)
 }
 
 // Post mocks base method.
@@ -222,16 +419,246 @@ func (mr *MockAuthorMockRecorder) Post(c, auth interface{}) *gomock.Call {
 }
 
 // Update mocks base method.
-func (m *MockAuthor) Update(c *gofr.Context, id int, author models.Author) (models.Author, error) {
+func (m *MockAuthor) Update(c *gofr.Context, id int, author models.Author, version int) (models.Author, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Update", c, id, author)
+	ret := m.ctrl.Call(m, "Update", c, id, author, version)
 	ret0, _ := ret[0].(models.Author)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // Update indicates an expected call of Update.
-func (mr *MockAuthorMockRecorder) Update(c, id, author interface{}) *gomock.Call {
+func (mr *MockAuthorMockRecorder) Update(c, id, author, version interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockAuthor)(nil).Update), c, id, author, version)
+}
+
+// MockPublisher is a mock of Publisher interface.
+type MockPublisher struct {
+	ctrl     *gomock.Controller
+	recorder *MockPublisherMockRecorder
+}
+
+// MockPublisherMockRecorder is the mock recorder for MockPublisher.
+type MockPublisherMockRecorder struct {
+	mock *MockPublisher
+}
+
+// NewMockPublisher creates a new mock instance.
+func NewMockPublisher(ctrl *gomock.Controller) *MockPublisher {
+	mock := &MockPublisher{ctrl: ctrl}
+	mock.recorder = &MockPublisherMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockPublisher) EXPECT() *MockPublisherMockRecorder {
+	return m.recorder
+}
+
+// Delete mocks base method.
+func (m *MockPublisher) Delete(c *gofr.Context, id int) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", c, id)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockPublisherMockRecorder) Delete(c, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockPublisher)(nil).Delete), c, id)
+}
+
+// GetAll mocks base method.
+func (m *MockPublisher) GetAll(c *gofr.Context) ([]models.Publisher, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAll", c)
+	ret0, _ := ret[0].([]models.Publisher)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAll indicates an expected call of GetAll.
+func (mr *MockPublisherMockRecorder) GetAll(c interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAll", reflect.TypeOf((*MockPublisher)(nil).GetAll), c)
+}
+
+// GetByID mocks base method.
+func (m *MockPublisher) GetByID(c *gofr.Context, id int) (models.Publisher, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByID", c, id)
+	ret0, _ := ret[0].(models.Publisher)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByID indicates an expected call of GetByID.
+func (mr *MockPublisherMockRecorder) GetByID(c, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByID", reflect.TypeOf((*MockPublisher)(nil).GetByID), c, id)
+}
+
+// GetByName mocks base method.
+func (m *MockPublisher) GetByName(c *gofr.Context, name string) (models.Publisher, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByName", c, name)
+	ret0, _ := ret[0].(models.Publisher)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByName indicates an expected call of GetByName.
+func (mr *MockPublisherMockRecorder) GetByName(c, name interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByName", reflect.TypeOf((*MockPublisher)(nil).GetByName), c, name)
+}
+
+// LockForUpdate mocks base method.
+func (m *MockPublisher) LockForUpdate(c *gofr.Context, id int) (models.Publisher, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LockForUpdate", c, id)
+	ret0, _ := ret[0].(models.Publisher)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// LockForUpdate indicates an expected call of LockForUpdate.
+func (mr *MockPublisherMockRecorder) LockForUpdate(c, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LockForUpdate", reflect.TypeOf((*MockPublisher)(nil).LockForUpdate), c, id)
+}
+
+// Post mocks base method.
+func (m *MockPublisher) Post(c *gofr.Context, publisher models.Publisher) (models.Publisher, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Post", c, publisher)
+	ret0, _ := ret[0].(models.Publisher)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Post indicates an expected call of Post.
+func (mr *MockPublisherMockRecorder) Post(c, publisher interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Post", reflect.TypeOf((*MockPublisher)(nil).Post), c, publisher)
+}
+
+// Update mocks base method.
+func (m *MockPublisher) Update(c *gofr.Context, id int, publisher models.Publisher) (models.Publisher, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Update", c, id, publisher)
+	ret0, _ := ret[0].(models.Publisher)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Update indicates an expected call of Update.
+func (mr *MockPublisherMockRecorder) Update(c, id, publisher interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockPublisher)(nil).Update), c, id, publisher)
+}
+
+// MockTx is a mock of Tx interface.
+type MockTx struct {
+	ctrl     *gomock.Controller
+	recorder *MockTxMockRecorder
+}
+
+// MockTxMockRecorder is the mock recorder for MockTx.
+type MockTxMockRecorder struct {
+	mock *MockTx
+}
+
+// NewMockTx creates a new mock instance.
+func NewMockTx(ctrl *gomock.Controller) *MockTx {
+	mock := &MockTx{ctrl: ctrl}
+	mock.recorder = &MockTxMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockTx) EXPECT() *MockTxMockRecorder {
+	return m.recorder
+}
+
+// Author mocks base method.
+func (m *MockTx) Author() Author {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Author")
+	ret0, _ := ret[0].(Author)
+	return ret0
+}
+
+// Author indicates an expected call of Author.
+func (mr *MockTxMockRecorder) Author() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Author", reflect.TypeOf((*MockTx)(nil).Author))
+}
+
+// Book mocks base method.
+func (m *MockTx) Book() Book {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Book")
+	ret0, _ := ret[0].(Book)
+	return ret0
+}
+
+// Book indicates an expected call of Book.
+func (mr *MockTxMockRecorder) Book() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Book", reflect.TypeOf((*MockTx)(nil).Book))
+}
+
+// Publisher mocks base method.
+func (m *MockTx) Publisher() Publisher {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Publisher")
+	ret0, _ := ret[0].(Publisher)
+	return ret0
+}
+
+// Publisher indicates an expected call of Publisher.
+func (mr *MockTxMockRecorder) Publisher() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Publisher", reflect.TypeOf((*MockTx)(nil).Publisher))
+}
+
+// MockUnitOfWork is a mock of UnitOfWork interface.
+type MockUnitOfWork struct {
+	ctrl     *gomock.Controller
+	recorder *MockUnitOfWorkMockRecorder
+}
+
+// MockUnitOfWorkMockRecorder is the mock recorder for MockUnitOfWork.
+type MockUnitOfWorkMockRecorder struct {
+	mock *MockUnitOfWork
+}
+
+// NewMockUnitOfWork creates a new mock instance.
+func NewMockUnitOfWork(ctrl *gomock.Controller) *MockUnitOfWork {
+	mock := &MockUnitOfWork{ctrl: ctrl}
+	mock.recorder = &MockUnitOfWorkMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockUnitOfWork) EXPECT() *MockUnitOfWorkMockRecorder {
+	return m.recorder
+}
+
+// Do mocks base method.
+func (m *MockUnitOfWork) Do(c *gofr.Context, work func(Tx) error) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Do", c, work)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Do indicates an expected call of Do.
+func (mr *MockUnitOfWorkMockRecorder) Do(c, work interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockAuthor)(nil).Update), c, id, author)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Do", reflect.TypeOf((*MockUnitOfWork)(nil).Do), c, work)
 }
@@ -0,0 +1,150 @@
+package publisher
+
+import (
+	"database/sql"
+
+	"developer.zopsmart.com/go/gofr/pkg/gofr"
+	"mytest/datastore"
+	"mytest/models"
+	"mytest/models/errors"
+)
+
+type Datastore struct {
+	// tx is the transaction the statements run in, the DB of the context when nil
+	tx *sql.Tx
+}
+
+func New() Datastore {
+	return Datastore{}
+}
+
+// NewTx is to build the publisher Datastore running its statements in the given transaction
+func NewTx(tx *sql.Tx) Datastore {
+	return Datastore{tx: tx}
+}
+
+// db is to get where the statements run, the transaction of the datastore or else the DB of the context
+func (d Datastore) db(c *gofr.Context) datastore.Executor {
+	if d.tx != nil {
+		return d.tx
+	}
+
+	return c.DB()
+}
+
+// Post method is to post the data in Publisher table
+func (d Datastore) Post(c *gofr.Context, publisher models.Publisher) (models.Publisher, error) {
+	// inserting data into db, publisherId is generated by AUTO_INCREMENT
+	res, err := d.db(c).Exec("insert into Publisher(name) values (?)", publisher.Name)
+	if err != nil {
+		return models.Publisher{}, conflict(err)
+	}
+
+	id, err := res.LastInsertId()
+	if err != nil {
+		return models.Publisher{}, err
+	}
+
+	publisher.PublisherID = int(id)
+
+	return publisher, nil
+}
+
+// GetAll method is to get all the publishers from Publisher table, ordered by name
+func (d Datastore) GetAll(c *gofr.Context) ([]models.Publisher, error) {
+	rows, err := d.db(c).Query("select " + publisherColumns + " from Publisher order by name")
+	if err != nil {
+		return nil, err
+	}
+
+	// Closing rows
+	defer rows.Close()
+
+	var publishers []models.Publisher
+
+	for rows.Next() {
+		publisher, err := scanPublisher(rows)
+		if err != nil {
+			return []models.Publisher{}, err
+		}
+
+		publishers = append(publishers, publisher)
+	}
+
+	return publishers, nil
+}
+
+// GetByID method is to get the publisher by its ID, sql.ErrNoRows is returned when it does not exist
+func (d Datastore) GetByID(c *gofr.Context, id int) (models.Publisher, error) {
+	row := d.db(c).QueryRow("select "+publisherColumns+" from Publisher where publisherId=?", id)
+
+	return scanPublisher(row)
+}
+
+// GetByName method is to get the publisher by its name ignoring the case, sql.ErrNoRows is returned
+// when it does not exist
+func (d Datastore) GetByName(c *gofr.Context, name string) (models.Publisher, error) {
+	row := d.db(c).QueryRow("select "+publisherColumns+" from Publisher where LOWER(name)=LOWER(?)", name)
+
+	return scanPublisher(row)
+}
+
+// LockForUpdate method is to get the publisher holding an exclusive lock on its row, so that no book is
+// inserted for it nor moved to it by another transaction until the transaction of the datastore ends,
+// sql.ErrNoRows is returned when there is none
+func (d Datastore) LockForUpdate(c *gofr.Context, id int) (models.Publisher, error) {
+	row := d.db(c).QueryRow("select "+publisherColumns+" from Publisher where publisherId=? FOR UPDATE", id)
+
+	return scanPublisher(row)
+}
+
+// Update method is to update the data in Publisher table and read it back
+func (d Datastore) Update(c *gofr.Context, id int, publisher models.Publisher) (models.Publisher, error) {
+	_, err := d.db(c).Exec("UPDATE Publisher SET name=? WHERE publisherId=?", publisher.Name, id)
+	if err != nil {
+		return models.Publisher{}, conflict(err)
+	}
+
+	// reading back the stored publisher
+	return d.GetByID(c, id)
+}
+
+// Delete method is to delete the publisher from Publisher table
+func (d Datastore) Delete(c *gofr.Context, id int) (int, error) {
+	res, err := d.db(c).Exec("DELETE FROM Publisher where publisherId=?", id)
+	if err != nil {
+		return 0, err
+	}
+
+	rowAffected, err := res.RowsAffected()
+	if err != nil {
+		return 0, err
+	}
+
+	return int(rowAffected), nil
+}
+
+// conflict is to map a clash with the name of another publisher to a Conflict
+func conflict(err error) error {
+	return datastore.Conflict(err, errors.CodePublisherExists, "publisher already exists")
+}
+
+// publisherFields is the mapping of the Publisher columns to the fields of publisher
+func publisherFields(publisher *models.Publisher) []datastore.Field {
+	return []datastore.Field{{Column: "publisherId", Dest: &publisher.PublisherID},
+		{Column: "name", Dest: &publisher.Name}}
+}
+
+// publisherColumns is the column list every read of a publisher selects
+var publisherColumns = datastore.Columns(publisherFields(&models.Publisher{}))
+
+// scanPublisher is to scan a single Publisher row selected with publisherColumns
+func scanPublisher(row datastore.Row) (models.Publisher, error) {
+	var publisher models.Publisher
+
+	if err := datastore.Scan(row, publisherFields(&publisher)); err != nil {
+		return models.Publisher{}, err
+	}
+
+	return publisher, nil
+}
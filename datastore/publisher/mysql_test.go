@@ -0,0 +1,346 @@
+package publisher
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"developer.zopsmart.com/go/gofr/pkg/gofr"
+	"errors"
+	"log"
+	"reflect"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/go-sql-driver/mysql"
+
+	"mytest/models"
+	modelErrors "mytest/models/errors"
+)
+
+// TestPublisher_Post Testing post publisher
+func TestPublisher_Post(t *testing.T) {
+	testcases := []struct {
+		desc    string
+		req     models.Publisher
+		resp    models.Publisher
+		res     driver.Result
+		execErr error
+		err     error
+	}{
+		{desc: "valid details", req: models.Publisher{Name: "Penguin"}, resp: models.Publisher{PublisherID: 1,
+			Name: "Penguin"}, res: sqlmock.NewResult(1, 1)},
+		{desc: "error in insert", req: models.Publisher{Name: "Penguin"}, res: sqlmock.NewResult(0, 0),
+			execErr: errors.New("error"), err: errors.New("error")},
+		{desc: "error in lastInsertId", req: models.Publisher{Name: "Penguin"},
+			res: sqlmock.NewErrorResult(errors.New("error")), err: errors.New("error")},
+		{desc: "name taken", req: models.Publisher{Name: "Penguin"}, res: sqlmock.NewResult(0, 0),
+			err: modelErrors.Conflict{Code: modelErrors.CodePublisherExists,
+				Reason: "publisher already exists"},
+			execErr: &mysql.MySQLError{Number: 1062, Message: "Duplicate entry for key 'Publisher.uq_publisher_name'"}},
+	}
+
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	if err != nil {
+		log.Printf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+
+	app := gofr.New()
+	app.DB().DB = db
+
+	ctx := gofr.NewContext(nil, nil, app)
+
+	defer db.Close()
+
+	for i, v := range testcases {
+		mock.ExpectExec("insert into Publisher(name) values (?)").WithArgs(v.req.Name).
+			WillReturnResult(v.res).WillReturnError(v.execErr)
+
+		datastore := New()
+
+		resp, err := datastore.Post(ctx, v.req)
+
+		if !reflect.DeepEqual(resp, v.resp) {
+			t.Errorf("desc : %v ,[TEST%d]Failed. Got %v\tExpected %v\n", v.desc, i+1, resp, v.resp)
+		}
+
+		if !reflect.DeepEqual(err, v.err) {
+			t.Errorf("desc : %v ,[TEST%d]Failed. Got %v\tExpected %v\n", v.desc, i+1, err, v.err)
+		}
+	}
+}
+
+// TestPublisher_GetAll Testing get all publishers
+func TestPublisher_GetAll(t *testing.T) {
+	testcases := []struct {
+		desc string
+		resp []models.Publisher
+		rows *sqlmock.Rows
+		err  error
+	}{
+		{desc: "valid details", resp: []models.Publisher{{PublisherID: 2, Name: "Arihant"},
+			{PublisherID: 1, Name: "Penguin"}}, rows: sqlmock.NewRows([]string{"publisherId", "name"}).
+			AddRow(2, "Arihant").AddRow(1, "Penguin")},
+		{desc: "error in scanning", resp: []models.Publisher{}, rows: sqlmock.NewRows([]string{"publisherId", "name"}).
+			AddRow("abc", "Arihant")},
+		{desc: "error in select all", rows: sqlmock.NewRows([]string{}), err: errors.New("error in select all")},
+	}
+
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	if err != nil {
+		log.Printf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+
+	app := gofr.New()
+	app.DB().DB = db
+
+	ctx := gofr.NewContext(nil, nil, app)
+
+	defer db.Close()
+
+	for i, v := range testcases {
+		mock.ExpectQuery("select publisherId, name from Publisher order by name").WillReturnRows(v.rows).WillReturnError(v.err)
+
+		datastore := New()
+
+		resp, err := datastore.GetAll(ctx)
+
+		if !reflect.DeepEqual(resp, v.resp) {
+			t.Errorf("desc : %v ,[TEST%d]Failed. Got %v\tExpected %v\n", v.desc, i+1, resp, v.resp)
+		}
+
+		if err != nil {
+			log.Printf("desc : %v ,[TEST%d]Failed. Got %v", v.desc, i+1, err)
+		}
+	}
+}
+
+// TestPublisher_GetByID Testing get publisher by id
+func TestPublisher_GetByID(t *testing.T) {
+	testcases := []struct {
+		desc string
+		id   int
+		resp models.Publisher
+		rows *sqlmock.Rows
+		err  error
+	}{
+		{desc: "valid", id: 1, resp: models.Publisher{PublisherID: 1, Name: "Penguin"},
+			rows: sqlmock.NewRows([]string{"publisherId", "name"}).AddRow(1, "Penguin")},
+		{desc: "id not exist", id: 11, rows: sqlmock.NewRows([]string{"publisherId", "name"}), err: sql.ErrNoRows},
+	}
+
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	if err != nil {
+		log.Printf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+
+	app := gofr.New()
+	app.DB().DB = db
+
+	ctx := gofr.NewContext(nil, nil, app)
+
+	defer db.Close()
+
+	for i, v := range testcases {
+		mock.ExpectQuery("select publisherId, name from Publisher where publisherId=?").WithArgs(v.id).WillReturnRows(v.rows)
+
+		datastore := New()
+
+		resp, err := datastore.GetByID(ctx, v.id)
+
+		if !reflect.DeepEqual(resp, v.resp) {
+			t.Errorf("desc : %v ,[TEST%d]Failed. Got %v\tExpected %v\n", v.desc, i+1, resp, v.resp)
+		}
+
+		if !reflect.DeepEqual(err, v.err) {
+			t.Errorf("desc : %v ,[TEST%d]Failed. Got %v\tExpected %v\n", v.desc, i+1, err, v.err)
+		}
+	}
+}
+
+// TestPublisher_LockForUpdate Testing get publisher by id holding an exclusive lock on its row
+func TestPublisher_LockForUpdate(t *testing.T) {
+	testcases := []struct {
+		desc string
+		id   int
+		resp models.Publisher
+		rows *sqlmock.Rows
+		err  error
+	}{
+		{desc: "valid", id: 1, resp: models.Publisher{PublisherID: 1, Name: "Penguin"},
+			rows: sqlmock.NewRows([]string{"publisherId", "name"}).AddRow(1, "Penguin")},
+		{desc: "id not exist", id: 11, rows: sqlmock.NewRows([]string{"publisherId", "name"}), err: sql.ErrNoRows},
+	}
+
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	if err != nil {
+		log.Printf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+
+	app := gofr.New()
+	app.DB().DB = db
+
+	ctx := gofr.NewContext(nil, nil, app)
+
+	defer db.Close()
+
+	for i, v := range testcases {
+		mock.ExpectBegin()
+		mock.ExpectQuery("select publisherId, name from Publisher where publisherId=? FOR UPDATE").WithArgs(v.id).
+			WillReturnRows(v.rows)
+
+		tx, err := db.Begin()
+		if err != nil {
+			t.Fatalf("desc : %v ,[TEST%d]Failed. Got %v\n", v.desc, i+1, err)
+		}
+
+		resp, err := NewTx(tx).LockForUpdate(ctx, v.id)
+
+		if !reflect.DeepEqual(resp, v.resp) {
+			t.Errorf("desc : %v ,[TEST%d]Failed. Got %v\tExpected %v\n", v.desc, i+1, resp, v.resp)
+		}
+
+		if !reflect.DeepEqual(err, v.err) {
+			t.Errorf("desc : %v ,[TEST%d]Failed. Got %v\tExpected %v\n", v.desc, i+1, err, v.err)
+		}
+
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Errorf("desc : %v ,[TEST%d]Failed. Got %v\n", v.desc, i+1, err)
+		}
+	}
+}
+
+// TestPublisher_GetByName Testing get publisher by its name whatever the case
+func TestPublisher_GetByName(t *testing.T) {
+	testcases := []struct {
+		desc string
+		name string
+		resp models.Publisher
+		rows *sqlmock.Rows
+		err  error
+	}{
+		{desc: "valid", name: "PENGUIN", resp: models.Publisher{PublisherID: 1, Name: "Penguin"},
+			rows: sqlmock.NewRows([]string{"publisherId", "name"}).AddRow(1, "Penguin")},
+		{desc: "name not exist", name: "Lenin", rows: sqlmock.NewRows([]string{"publisherId", "name"}),
+			err: sql.ErrNoRows},
+	}
+
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	if err != nil {
+		log.Printf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+
+	app := gofr.New()
+	app.DB().DB = db
+
+	ctx := gofr.NewContext(nil, nil, app)
+
+	defer db.Close()
+
+	for i, v := range testcases {
+		mock.ExpectQuery("select publisherId, name from Publisher where LOWER(name)=LOWER(?)").WithArgs(v.name).WillReturnRows(v.rows)
+
+		datastore := New()
+
+		resp, err := datastore.GetByName(ctx, v.name)
+
+		if !reflect.DeepEqual(resp, v.resp) {
+			t.Errorf("desc : %v ,[TEST%d]Failed. Got %v\tExpected %v\n", v.desc, i+1, resp, v.resp)
+		}
+
+		if !reflect.DeepEqual(err, v.err) {
+			t.Errorf("desc : %v ,[TEST%d]Failed. Got %v\tExpected %v\n", v.desc, i+1, err, v.err)
+		}
+	}
+}
+
+// TestPublisher_Put Testing update publisher, the stored publisher is read back
+func TestPublisher_Put(t *testing.T) {
+	testcases := []struct {
+		desc    string
+		id      int
+		req     models.Publisher
+		resp    models.Publisher
+		execErr error
+	}{
+		{desc: "valid", id: 1, req: models.Publisher{Name: "Penguin Books"},
+			resp: models.Publisher{PublisherID: 1, Name: "Penguin Books"}},
+		{desc: "error in exec", id: 2, req: models.Publisher{Name: "Arihant"}, execErr: errors.New("error in exec")},
+	}
+
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	if err != nil {
+		log.Printf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+
+	app := gofr.New()
+	app.DB().DB = db
+
+	ctx := gofr.NewContext(nil, nil, app)
+
+	defer db.Close()
+
+	for i, v := range testcases {
+		mock.ExpectExec("UPDATE Publisher SET name=? WHERE publisherId=?").WithArgs(v.req.Name, v.id).
+			WillReturnResult(sqlmock.NewResult(0, 1)).WillReturnError(v.execErr)
+
+		if v.execErr == nil {
+			mock.ExpectQuery("select publisherId, name from Publisher where publisherId=?").WithArgs(v.id).
+				WillReturnRows(sqlmock.NewRows([]string{"publisherId", "name"}).AddRow(v.id, v.req.Name))
+		}
+
+		datastore := New()
+
+		resp, err := datastore.Update(ctx, v.id, v.req)
+
+		if !reflect.DeepEqual(resp, v.resp) {
+			t.Errorf("desc : %v ,[TEST%d]Failed. Got %v\tExpected %v\n", v.desc, i+1, resp, v.resp)
+		}
+
+		if err != nil {
+			log.Printf("desc : %v ,[TEST%d]Failed. Got %v", v.desc, i+1, err)
+		}
+	}
+}
+
+// TestPublisher_Delete Testing delete publisher
+func TestPublisher_Delete(t *testing.T) {
+	testcases := []struct {
+		desc string
+		id   int
+		resp int
+		res  driver.Result
+		err  error
+	}{
+		{desc: "valid", id: 1, resp: 1, res: sqlmock.NewResult(0, 1)},
+		{desc: "error in exec", id: 11, res: sqlmock.NewResult(0, 0), err: errors.New("error")},
+		{desc: "error in rowAffected", id: 4, res: sqlmock.NewErrorResult(errors.New("error"))},
+	}
+
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	if err != nil {
+		log.Printf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+
+	app := gofr.New()
+	app.DB().DB = db
+
+	ctx := gofr.NewContext(nil, nil, app)
+
+	defer db.Close()
+
+	for i, v := range testcases {
+		mock.ExpectExec("DELETE FROM Publisher where publisherId=?").WithArgs(v.id).
+			WillReturnResult(v.res).WillReturnError(v.err)
+
+		datastore := New()
+
+		resp, err := datastore.Delete(ctx, v.id)
+
+		if !reflect.DeepEqual(resp, v.resp) {
+			t.Errorf("desc : %v ,[TEST%d]Failed. Got %v\tExpected %v\n", v.desc, i+1, resp, v.resp)
+		}
+
+		if err != nil {
+			log.Printf("desc : %v ,[TEST%d]Failed. Got %v", v.desc, i+1, err)
+		}
+	}
+}
@@ -0,0 +1,37 @@
+package datastore
+
+import (
+	"strings"
+)
+
+// Row is implemented by both *sql.Row and *sql.Rows
+type Row interface {
+	Scan(dest ...interface{}) error
+}
+
+// Field is a column of a table along with the field of a model it is read into
+type Field struct {
+	Column string
+	Dest   interface{}
+}
+
+// Columns method is to get the comma separated column list of the fields, which is what a read selects
+// so that the columns are scanned by name whatever their order in the table
+func Columns(fields []Field) string {
+	columns := make([]string, len(fields))
+	for i := range fields {
+		columns[i] = fields[i].Column
+	}
+
+	return strings.Join(columns, ", ")
+}
+
+// Scan method is to read a row selected with the Columns of the fields into their destinations
+func Scan(row Row, fields []Field) error {
+	dest := make([]interface{}, len(fields))
+	for i := range fields {
+		dest[i] = fields[i].Dest
+	}
+
+	return row.Scan(dest...)
+}
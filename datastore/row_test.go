@@ -0,0 +1,39 @@
+package datastore
+
+import (
+	"log"
+	"reflect"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+)
+
+// TestScan function is to test that a row is read into the fields by the order of their columns
+func TestScan(t *testing.T) {
+	var (
+		id   int
+		name string
+	)
+
+	fields := []Field{{Column: "name", Dest: &name}, {Column: "publisherId", Dest: &id}}
+
+	if columns := Columns(fields); columns != "name, publisherId" {
+		t.Errorf("desc : columns ,Failed. Got %v\tExpected %v\n", columns, "name, publisherId")
+	}
+
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	if err != nil {
+		log.Printf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+
+	defer db.Close()
+
+	mock.ExpectQuery("select name, publisherId from Publisher").
+		WillReturnRows(sqlmock.NewRows([]string{"name", "publisherId"}).AddRow("Penguin", 1))
+
+	err = Scan(db.QueryRow("select "+Columns(fields)+" from Publisher"), fields)
+
+	if err != nil || !reflect.DeepEqual([]interface{}{id, name}, []interface{}{1, "Penguin"}) {
+		t.Errorf("desc : scan ,Failed. Got %v, %v, %v\tExpected 1, Penguin\n", id, name, err)
+	}
+}
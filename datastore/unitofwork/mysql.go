@@ -0,0 +1,61 @@
+package unitofwork
+
+import (
+	"database/sql"
+
+	"developer.zopsmart.com/go/gofr/pkg/gofr"
+	"mytest/datastore"
+	"mytest/datastore/author"
+	"mytest/datastore/book"
+	"mytest/datastore/publisher"
+)
+
+type UnitOfWork struct {
+}
+
+func New() UnitOfWork {
+	return UnitOfWork{}
+}
+
+// Do method is to run the work in one DB transaction, it is committed when the work returns nil and rolled
+// back on any error or panic of the work
+func (u UnitOfWork) Do(c *gofr.Context, work func(tx datastore.Tx) error) error {
+	sqlTx, err := c.DB().Begin()
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		if p := recover(); p != nil {
+			_ = sqlTx.Rollback()
+
+			panic(p)
+		}
+	}()
+
+	if err := work(tx{sqlTx: sqlTx}); err != nil {
+		// the error of the work is what the caller gets, a failed rollback is dropped along with the connection
+		_ = sqlTx.Rollback()
+
+		return err
+	}
+
+	return sqlTx.Commit()
+}
+
+// tx is the datastores running their statements in one transaction
+type tx struct {
+	sqlTx *sql.Tx
+}
+
+func (t tx) Book() datastore.Book {
+	return book.NewTx(t.sqlTx)
+}
+
+func (t tx) Author() datastore.Author {
+	return author.NewTx(t.sqlTx)
+}
+
+func (t tx) Publisher() datastore.Publisher {
+	return publisher.NewTx(t.sqlTx)
+}
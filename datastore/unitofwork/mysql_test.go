@@ -0,0 +1,64 @@
+package unitofwork
+
+import (
+	"developer.zopsmart.com/go/gofr/pkg/gofr"
+	"errors"
+	"log"
+	"reflect"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+
+	"mytest/datastore"
+)
+
+// TestUnitOfWork_Do is to check that the work is committed when it succeeds and rolled back on any error
+func TestUnitOfWork_Do(t *testing.T) {
+	testcases := []struct {
+		desc    string
+		workErr error
+		err     error
+	}{
+		{desc: "committed"},
+		{desc: "rolled back", workErr: errors.New("author not found"), err: errors.New("author not found")},
+	}
+
+	for i, v := range testcases {
+		db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+		if err != nil {
+			log.Printf("an error '%s' was not expected when opening a stub database connection", err)
+		}
+
+		app := gofr.New()
+		app.DB().DB = db
+
+		ctx := gofr.NewContext(nil, nil, app)
+
+		mock.ExpectBegin()
+		mock.ExpectExec("DELETE FROM Book where bookId=?").WithArgs(1).WillReturnResult(sqlmock.NewResult(0, 1))
+
+		if v.workErr == nil {
+			mock.ExpectCommit()
+		} else {
+			mock.ExpectRollback()
+		}
+
+		err = New().Do(ctx, func(tx datastore.Tx) error {
+			if _, err := tx.Book().Delete(ctx, 1, 0); err != nil {
+				return err
+			}
+
+			return v.workErr
+		})
+
+		if !reflect.DeepEqual(err, v.err) {
+			t.Errorf("desc : %v ,[TEST%d]Failed. Got %v\tExpected %v\n", v.desc, i+1, err, v.err)
+		}
+
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Errorf("desc : %v ,[TEST%d]Failed. Got %v\n", v.desc, i+1, err)
+		}
+
+		db.Close()
+	}
+}
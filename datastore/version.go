@@ -0,0 +1,43 @@
+package datastore
+
+import (
+	"database/sql"
+)
+
+// VersionClause method is to get the condition a statement changing a row adds to be made only on the given
+// version of the row, there is none when the version is 0
+func VersionClause(version int) string {
+	if version == 0 {
+		return ""
+	}
+
+	return " AND version=?"
+}
+
+// VersionArgs method is to append the version to the args of a statement built with VersionClause
+func VersionArgs(args []interface{}, version int) []interface{} {
+	if version == 0 {
+		return args
+	}
+
+	return append(args, version)
+}
+
+// CheckVersion method is to tell from the result of a statement built with VersionClause whether the row was
+// at the given version, sql.ErrNoRows is returned when no row was
+func CheckVersion(res sql.Result, version int) error {
+	if version == 0 {
+		return nil
+	}
+
+	rowAffected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowAffected == 0 {
+		return sql.ErrNoRows
+	}
+
+	return nil
+}
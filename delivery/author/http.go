@@ -1,20 +1,23 @@
 package author
 
 import (
-	"developer.zopsmart.com/go/gofr/pkg/errors"
 	"developer.zopsmart.com/go/gofr/pkg/gofr"
+	"developer.zopsmart.com/go/gofr/pkg/gofr/types"
 	"strconv"
 
+	"mytest/delivery"
 	"mytest/models"
+	"mytest/models/errors"
 	"mytest/service"
 )
 
 type Delivery struct {
 	service service.Author
+	book    service.Book
 }
 
-func New(author service.Author) Delivery {
-	return Delivery{author}
+func New(author service.Author, book service.Book) Delivery {
+	return Delivery{service: author, book: book}
 }
 
 // Create Request method is to post request
@@ -25,20 +28,72 @@ func (d Delivery) Create(c *gofr.Context) (interface{}, error) {
 		return models.Author{}, err
 	}
 
-	return d.service.Post(c, author)
+	return withETag(d.service.Post(c, author))
 }
 
-// Update Request method is to update request
+// GetAll method is to get all the authors
+func (d Delivery) GetAll(c *gofr.Context) (interface{}, error) {
+	return d.service.GetAll(c)
+}
+
+// GetByID method is to get the author by its id
+func (d Delivery) GetByID(c *gofr.Context) (interface{}, error) {
+	id := c.PathParam("id")
+
+	if id == "" {
+		return models.Author{}, errors.InvalidParam{Code: errors.CodeMissingParam, Params: []string{"id"}}
+	}
+
+	id2, err := strconv.Atoi(id)
+	if err != nil {
+		return models.Author{}, errors.InvalidParam{Code: errors.CodeInvalidParam, Params: []string{"id"}}
+	}
+
+	return withETag(d.service.GetByID(c, id2))
+}
+
+// GetBooks method is to get a page of the books written by the author
+func (d Delivery) GetBooks(c *gofr.Context) (interface{}, error) {
+	id := c.PathParam("id")
+
+	if id == "" {
+		return []models.Book{}, errors.InvalidParam{Code: errors.CodeMissingParam, Params: []string{"id"}}
+	}
+
+	id2, err := strconv.Atoi(id)
+	if err != nil {
+		return []models.Book{}, errors.InvalidParam{Code: errors.CodeInvalidParam, Params: []string{"id"}}
+	}
+
+	page, err := delivery.GetPage(c)
+	if err != nil {
+		return []models.Book{}, err
+	}
+
+	books, meta, err := d.book.GetByAuthorID(c, id2, page)
+	if err != nil {
+		return []models.Book{}, err
+	}
+
+	return types.Response{Data: books, Meta: meta}, nil
+}
+
+// Update Request method is to update request, only if the author is still at the version in If-Match when given
 func (d Delivery) Update(c *gofr.Context) (interface{}, error) {
 	id := c.PathParam("id")
 
 	if id == "" {
-		return models.Author{}, errors.MissingParam{Param: []string{id}}
+		return models.Author{}, errors.InvalidParam{Code: errors.CodeMissingParam, Params: []string{"id"}}
 	}
 
 	id2, err := strconv.Atoi(id)
 	if err != nil {
-		return models.Author{}, errors.InvalidParam{Param: []string{id}}
+		return models.Author{}, errors.InvalidParam{Code: errors.CodeInvalidParam, Params: []string{"id"}}
+	}
+
+	version, err := delivery.GetVersion(c, "Author", id2, d.currentVersion)
+	if err != nil {
+		return models.Author{}, err
 	}
 
 	var author models.Author
@@ -47,21 +102,95 @@ func (d Delivery) Update(c *gofr.Context) (interface{}, error) {
 		return models.Author{}, err
 	}
 
-	return d.service.Update(c, id2, author)
+	return withETag(d.service.Update(c, id2, author, version))
+}
+
+// Patch method is to partially update the Author with a JSON merge patch, If-Match is honoured as in Update
+func (d Delivery) Patch(c *gofr.Context) (interface{}, error) {
+	id := c.PathParam("id")
+
+	if id == "" {
+		return models.Author{}, errors.InvalidParam{Code: errors.CodeMissingParam, Params: []string{"id"}}
+	}
+
+	id2, err := strconv.Atoi(id)
+	if err != nil {
+		return models.Author{}, errors.InvalidParam{Code: errors.CodeInvalidParam, Params: []string{"id"}}
+	}
+
+	version, err := delivery.GetVersion(c, "Author", id2, d.currentVersion)
+	if err != nil {
+		return models.Author{}, err
+	}
+
+	var patch models.AuthorPatch
+
+	if err := delivery.BindMergePatch(c, &patch); err != nil {
+		return models.Author{}, err
+	}
+
+	return withETag(d.service.Patch(c, id2, patch, version))
 }
 
-// Delete method is to delete data from request
+// Delete method is to delete data from request, the books query param selects how the books
+// of the author are handled and defaults to the AUTHOR_DELETE_POLICY config. If-Match is honoured as in Update.
 func (d Delivery) Delete(c *gofr.Context) (interface{}, error) {
 	id := c.PathParam("id")
 
 	if id == "" {
-		return 0, errors.MissingParam{Param: []string{id}}
+		return 0, errors.InvalidParam{Code: errors.CodeMissingParam, Params: []string{"id"}}
 	}
 
 	id2, err := strconv.Atoi(id)
 	if err != nil {
-		return 0, errors.InvalidParam{Param: []string{id}}
+		return 0, errors.InvalidParam{Code: errors.CodeInvalidParam, Params: []string{"id"}}
+	}
+
+	policy, err := getDeletePolicy(c)
+	if err != nil {
+		return 0, err
+	}
+
+	version, err := delivery.GetVersion(c, "Author", id2, d.currentVersion)
+	if err != nil {
+		return 0, err
+	}
+
+	return d.service.Delete(c, id2, policy, version)
+}
+
+// withETag method is to respond an author along with the ETag of its version
+func withETag(author models.Author, err error) (interface{}, error) {
+	if err != nil {
+		return models.Author{}, err
+	}
+
+	return delivery.WithETag(author, author.Version), nil
+}
+
+// currentVersion method is to read the version the author is at, to tell which version of an If-Match list is honoured
+func (d Delivery) currentVersion(c *gofr.Context, id int) (int, error) {
+	author, err := d.service.GetByID(c, id)
+
+	return author.Version, err
+}
+
+// getDeletePolicy method is to read the books and reassignTo query params
+func getDeletePolicy(c *gofr.Context) (models.DeletePolicy, error) {
+	policy := models.DeletePolicy{Books: c.Param("books")}
+
+	if policy.Books == "" {
+		policy.Books = c.Config.GetOrDefault("AUTHOR_DELETE_POLICY", models.DeleteReject)
+	}
+
+	if reassignTo := c.Param("reassignTo"); reassignTo != "" {
+		id, err := strconv.Atoi(reassignTo)
+		if err != nil {
+			return models.DeletePolicy{}, errors.InvalidParam{Code: errors.CodeInvalidParam, Params: []string{"reassignTo"}}
+		}
+
+		policy.ReassignTo = id
 	}
 
-	return d.service.Delete(c, id2)
+	return policy, nil
 }
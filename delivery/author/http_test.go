@@ -1,17 +1,18 @@
 package author
 
 import (
-	"Three-Layer-Architecture/models"
-	"Three-Layer-Architecture/service"
 	"bytes"
 	"developer.zopsmart.com/go/gofr/pkg/gofr"
 	"developer.zopsmart.com/go/gofr/pkg/gofr/request"
 	"developer.zopsmart.com/go/gofr/pkg/gofr/responder"
+	"developer.zopsmart.com/go/gofr/pkg/gofr/types"
 	"encoding/json"
 	"errors"
 	"github.com/golang/mock/gomock"
 	"github.com/gorilla/mux"
 	"log"
+	"mytest/models"
+	"mytest/service"
 	"net/http"
 	"net/http/httptest"
 	"net/url"
@@ -20,6 +21,12 @@ import (
 	"testing"
 )
 
+// etagged is the response of a handler returning the author along with the ETag of its version
+func etagged(author models.Author) types.RawWithOptions {
+	return types.RawWithOptions{Data: types.Response{Data: author}, ContentType: "application/json",
+		Header: map[string]string{"ETag": `"` + strconv.Itoa(author.Version) + `"`}}
+}
+
 // TestPostAuthor function is to test post method
 func TestPostAuthor(t *testing.T) {
 	testcases := []struct {
@@ -29,17 +36,19 @@ func TestPostAuthor(t *testing.T) {
 		StatusCode int
 		err        error
 	}{
-		{desc: "valid", req: models.Author{AuthID: 1, FirstName: "Chetan", LastName: "Bhagat", Dob: "06/04/2001",
-			PenName: "Chetan"}, resp: models.Author{AuthID: 1, FirstName: "Chetan", LastName: "Bhagat", Dob: "06/04/2001",
-			PenName: "Chetan"}, StatusCode: http.StatusCreated},
+		{desc: "valid", req: models.Author{AuthID: 1, FirstName: "Chetan", LastName: "Bhagat",
+			Dob: models.NewDate(2001, 4, 6), PenName: "Chetan"}, resp: models.Author{AuthID: 1, FirstName: "Chetan",
+			LastName: "Bhagat", Dob: models.NewDate(2001, 4, 6), PenName: "Chetan", Version: 1},
+			StatusCode: http.StatusCreated},
 		{desc: "error in bind", req: "Sujeet", StatusCode: http.StatusBadRequest},
-		{desc: "errors from svc", req: models.Author{AuthID: -21, FirstName: "Sagar", LastName: "Bhagat", Dob: "06/04/2001",
-			PenName: "Chetan"}, StatusCode: http.StatusBadRequest, err: errors.New("invalid id")},
+		{desc: "errors from svc", req: models.Author{AuthID: -21, FirstName: "Sagar", LastName: "Bhagat",
+			Dob: models.NewDate(2001, 4, 6), PenName: "Chetan"}, StatusCode: http.StatusBadRequest,
+			err: errors.New("invalid id")},
 	}
 
 	ctr := gomock.NewController(t)
 	mockAuthor := service.NewMockAuthor(ctr)
-	delivery := New(mockAuthor)
+	delivery := New(mockAuthor, service.NewMockBook(ctr))
 	k := gofr.New()
 
 	for i, v := range testcases {
@@ -60,8 +69,13 @@ func TestPostAuthor(t *testing.T) {
 
 		author, err2 := delivery.Create(ctx)
 
-		if !reflect.DeepEqual(author, v.resp) {
-			t.Errorf("desc : %v ,[TEST%d]Failed. Got %v\tExpected %v\n", v.desc, i+1, author, v.resp)
+		expected := interface{}(v.resp)
+		if err2 == nil {
+			expected = etagged(v.resp)
+		}
+
+		if !reflect.DeepEqual(author, expected) {
+			t.Errorf("desc : %v ,[TEST%d]Failed. Got %v\tExpected %v\n", v.desc, i+1, author, expected)
 		}
 
 		if err2 != nil {
@@ -71,29 +85,193 @@ func TestPostAuthor(t *testing.T) {
 	}
 }
 
+// TestGetAllAuthors function is to test get all method
+func TestGetAllAuthors(t *testing.T) {
+	testcases := []struct {
+		desc       string
+		resp       []models.Author
+		StatusCode int
+		err        error
+	}{
+		{desc: "valid", resp: []models.Author{{AuthID: 1, FirstName: "Chetan", LastName: "Bhagat",
+			Dob: models.NewDate(2001, 4, 6), PenName: "Chetan"}}, StatusCode: http.StatusOK},
+	}
+
+	ctr := gomock.NewController(t)
+	mockAuthor := service.NewMockAuthor(ctr)
+	delivery := New(mockAuthor, service.NewMockBook(ctr))
+	k := gofr.New()
+
+	for i, v := range testcases {
+		r := httptest.NewRequest(http.MethodGet, "/authors", nil)
+		w := httptest.NewRecorder()
+
+		req := request.NewHTTPRequest(r)
+		resp := responder.NewContextualResponder(w, r)
+
+		ctx := gofr.NewContext(resp, req, k)
+
+		mockAuthor.EXPECT().GetAll(ctx).Return(v.resp, v.err).AnyTimes()
+
+		authors, err := delivery.GetAll(ctx)
+
+		if !reflect.DeepEqual(authors, v.resp) {
+			t.Errorf("desc : %v ,[TEST%d]Failed. Got %v\tExpected %v\n", v.desc, i+1, authors, v.resp)
+		}
+
+		if err != nil {
+			log.Printf("desc : %v ,[TEST%d] Got %v\n", v.desc, i+1, err)
+		}
+	}
+}
+
+// TestGetAuthor function is to test get by id method
+func TestGetAuthor(t *testing.T) {
+	testcases := []struct {
+		desc       string
+		id         string
+		resp       models.Author
+		StatusCode int
+		err        error
+	}{
+		{desc: "valid case", id: "1", resp: models.Author{AuthID: 1, FirstName: "Chetan", LastName: "Bhagat",
+			Dob: models.NewDate(2001, 4, 6), PenName: "Chetan", Version: 2}, StatusCode: http.StatusOK},
+		{desc: "missing param", id: "", StatusCode: http.StatusBadRequest},
+		{desc: "error in strconv", id: "abc", StatusCode: http.StatusBadRequest},
+	}
+
+	ctr := gomock.NewController(t)
+	mockAuthor := service.NewMockAuthor(ctr)
+	delivery := New(mockAuthor, service.NewMockBook(ctr))
+	k := gofr.New()
+
+	for i, v := range testcases {
+		r := httptest.NewRequest(http.MethodGet, "/author/"+v.id, nil)
+		w := httptest.NewRecorder()
+
+		r = mux.SetURLVars(r, map[string]string{"id": v.id})
+
+		req := request.NewHTTPRequest(r)
+		resp := responder.NewContextualResponder(w, r)
+
+		ctx := gofr.NewContext(resp, req, k)
+
+		id, err2 := strconv.Atoi(v.id)
+		if err2 != nil {
+			log.Printf("error in string conversion : %v\n", err2)
+		}
+
+		mockAuthor.EXPECT().GetByID(ctx, id).Return(v.resp, v.err).AnyTimes()
+
+		author, err := delivery.GetByID(ctx)
+
+		expected := interface{}(v.resp)
+		if err == nil {
+			expected = etagged(v.resp)
+		}
+
+		if !reflect.DeepEqual(author, expected) {
+			t.Errorf("desc : %v ,[TEST%d]Failed. Got %v\tExpected %v\n", v.desc, i+1, author, expected)
+		}
+
+		if err != nil {
+			log.Printf("desc : %v ,[TEST%d] Got %v\n", v.desc, i+1, err)
+		}
+	}
+}
+
+// TestGetAuthorBooks function is to test the listing of the books of an author
+func TestGetAuthorBooks(t *testing.T) {
+	books := []models.Book{{BookID: 1, AuthorID: 1, Title: "States", Publication: "Scholastic",
+		PublishedDate: models.NewDate(2016, 3, 16)}}
+
+	testcases := []struct {
+		desc   string
+		id     string
+		query  string
+		page   models.Page
+		books  []models.Book
+		meta   models.PageMeta
+		output interface{}
+		err    error
+	}{
+		{desc: "valid case", id: "1", books: books, meta: models.PageMeta{Total: 1},
+			output: types.Response{Data: books, Meta: models.PageMeta{Total: 1}}},
+		{desc: "pagination params", id: "2", query: "limit=1&offset=0&cursor=abc",
+			page: models.Page{Limit: 1, Cursor: "abc"}, books: books, meta: models.PageMeta{NextCursor: "next", Total: 3},
+			output: types.Response{Data: books, Meta: models.PageMeta{NextCursor: "next", Total: 3}}},
+		{desc: "missing param", id: "", output: []models.Book{}},
+		{desc: "error in strconv", id: "abc", output: []models.Book{}},
+		{desc: "invalid limit", id: "3", query: "limit=abc", output: []models.Book{}},
+		{desc: "author not found", id: "4", output: []models.Book{}, err: errors.New("author not found")},
+	}
+
+	ctr := gomock.NewController(t)
+	mockBook := service.NewMockBook(ctr)
+	delivery := New(service.NewMockAuthor(ctr), mockBook)
+	k := gofr.New()
+
+	for i, v := range testcases {
+		r := httptest.NewRequest(http.MethodGet, "/author/"+v.id+"/books?"+v.query, nil)
+		w := httptest.NewRecorder()
+
+		r = mux.SetURLVars(r, map[string]string{"id": v.id})
+
+		req := request.NewHTTPRequest(r)
+		resp := responder.NewContextualResponder(w, r)
+
+		ctx := gofr.NewContext(resp, req, k)
+
+		id, err2 := strconv.Atoi(v.id)
+		if err2 != nil {
+			log.Printf("error in string conversion : %v\n", err2)
+		}
+
+		mockBook.EXPECT().GetByAuthorID(ctx, id, v.page).Return(v.books, v.meta, v.err).AnyTimes()
+
+		output, err := delivery.GetBooks(ctx)
+
+		if !reflect.DeepEqual(output, v.output) {
+			t.Errorf("desc : %v ,[TEST%d]Failed. Got %v\tExpected %v\n", v.desc, i+1, output, v.output)
+		}
+
+		if err != nil {
+			log.Printf("desc : %v ,[TEST%d] Got %v\n", v.desc, i+1, err)
+		}
+	}
+}
+
 // TestUpdateAuthor function is to test put method
 func TestUpdateAuthor(t *testing.T) {
 	testcases := []struct {
 		desc       string
 		id         string
+		ifMatch    string
+		version    int
 		req        interface{}
 		resp       models.Author
 		StatusCode int
 		err        error
 	}{
 		{desc: "valid case", id: "1", req: models.Author{AuthID: 1, FirstName: "Rajan", LastName: "Sharma",
-			Dob: "26/04/2001", PenName: "Sharma"}, StatusCode: http.StatusOK},
+			Dob: models.NewDate(2001, 4, 26), PenName: "Sharma"}, StatusCode: http.StatusOK},
+		{desc: "if-match", id: "2", ifMatch: `"3"`, version: 3, req: models.Author{AuthID: 2, FirstName: "Rajan",
+			LastName: "Sharma", Dob: models.NewDate(2001, 4, 26), PenName: "Sharma"}, resp: models.Author{AuthID: 2,
+			FirstName: "Rajan", LastName: "Sharma", Dob: models.NewDate(2001, 4, 26), PenName: "Sharma", Version: 4},
+			StatusCode: http.StatusOK},
+		{desc: "invalid if-match", id: "3", ifMatch: "3", req: models.Author{AuthID: 3}, StatusCode: http.StatusBadRequest},
 		{desc: "missing param", id: "", StatusCode: http.StatusBadRequest},
 		{desc: "error in bind", id: "1", req: "something", StatusCode: http.StatusBadRequest},
-		{desc: "errors from svc", id: "11", req: models.Author{AuthID: -21, FirstName: "Sagar", LastName: "Bhagat", Dob: "06/04/2001",
-			PenName: "Chetan"}, StatusCode: http.StatusBadRequest, err: errors.New("invalid id")},
+		{desc: "errors from svc", id: "11", req: models.Author{AuthID: -21, FirstName: "Sagar", LastName: "Bhagat",
+			Dob: models.NewDate(2001, 4, 6), PenName: "Chetan"}, StatusCode: http.StatusBadRequest,
+			err: errors.New("invalid id")},
 		{desc: "error in strconv", id: "abc", req: models.Author{AuthID: 14, FirstName: "Rajan", LastName: "Sharma",
-			Dob: "26/04/2001", PenName: "Sharma"}, StatusCode: http.StatusBadRequest},
+			Dob: models.NewDate(2001, 4, 26), PenName: "Sharma"}, StatusCode: http.StatusBadRequest},
 	}
 
 	ctr := gomock.NewController(t)
 	mockAuthor := service.NewMockAuthor(ctr)
-	delivery := New(mockAuthor)
+	delivery := New(mockAuthor, service.NewMockBook(ctr))
 	k := gofr.New()
 
 	for i, v := range testcases {
@@ -109,6 +287,7 @@ func TestUpdateAuthor(t *testing.T) {
 		w := httptest.NewRecorder()
 
 		r = mux.SetURLVars(r, map[string]string{"id": v.id})
+		r.Header.Set("If-Match", v.ifMatch)
 
 		req := request.NewHTTPRequest(r)
 		resp := responder.NewContextualResponder(w, r)
@@ -120,13 +299,18 @@ func TestUpdateAuthor(t *testing.T) {
 			log.Printf("error in string conversion : %v\n", err2)
 		}
 
-		mockAuthor.EXPECT().Update(ctx, id, v.req).Return(v.resp, v.err).AnyTimes()
+		mockAuthor.EXPECT().Update(ctx, id, v.req, v.version).Return(v.resp, v.err).AnyTimes()
 
 		// Mocking Update
 		author, err3 := delivery.Update(ctx)
 
-		if !reflect.DeepEqual(author, v.resp) {
-			t.Errorf("desc : %v ,[TEST%d]Failed. Got %v\tExpected %v\n", v.desc, i+1, author, v.resp)
+		expected := interface{}(v.resp)
+		if err3 == nil {
+			expected = etagged(v.resp)
+		}
+
+		if !reflect.DeepEqual(author, expected) {
+			t.Errorf("desc : %v ,[TEST%d]Failed. Got %v\tExpected %v\n", v.desc, i+1, author, expected)
 		}
 
 		if err3 != nil {
@@ -135,34 +319,114 @@ func TestUpdateAuthor(t *testing.T) {
 	}
 }
 
+// TestPatchAuthor function is to test partial update of an author with a JSON merge patch
+func TestPatchAuthor(t *testing.T) {
+	penName := "Raj"
+
+	testcases := []struct {
+		desc  string
+		id    string
+		body  string
+		patch models.AuthorPatch
+		resp  models.Author
+		err   error
+	}{
+		{desc: "valid", id: "1", body: `{"penName":"Raj"}`, patch: models.AuthorPatch{PenName: &penName},
+			resp: models.Author{AuthID: 1, PenName: "Raj"}},
+		{desc: "empty patch", id: "2", body: `{}`, resp: models.Author{AuthID: 2}},
+		{desc: "removed field", id: "3", body: `{"penName":"Raj","dob":null}`, resp: models.Author{}},
+		{desc: "missing param", id: "", body: `{}`, resp: models.Author{}},
+		{desc: "invalid param", id: "abc", body: `{}`, resp: models.Author{}},
+		{desc: "error in bind", id: "4", body: `[]`, resp: models.Author{}},
+		{desc: "error from svc", id: "5", body: `{"penName":"Raj"}`, patch: models.AuthorPatch{PenName: &penName},
+			resp: models.Author{}, err: errors.New("error in patch")},
+	}
+
+	ctr := gomock.NewController(t)
+	mockAuthor := service.NewMockAuthor(ctr)
+	delivery := New(mockAuthor, service.NewMockBook(ctr))
+	k := gofr.New()
+
+	for i, v := range testcases {
+		r := httptest.NewRequest(http.MethodPatch, "/author/"+v.id, bytes.NewReader([]byte(v.body)))
+		w := httptest.NewRecorder()
+
+		r = mux.SetURLVars(r, map[string]string{"id": v.id})
+
+		req := request.NewHTTPRequest(r)
+		resp := responder.NewContextualResponder(w, r)
+
+		ctx := gofr.NewContext(resp, req, k)
+
+		id, err := strconv.Atoi(v.id)
+		if err != nil {
+			log.Printf("error in converting string : %v", err)
+		}
+
+		mockAuthor.EXPECT().Patch(ctx, id, v.patch, 0).Return(v.resp, v.err).AnyTimes()
+
+		output, err := delivery.Patch(ctx)
+
+		expected := interface{}(v.resp)
+		if err == nil {
+			expected = etagged(v.resp)
+		}
+
+		if !reflect.DeepEqual(output, expected) {
+			t.Errorf("desc : %v ,[TEST%d]Failed. Got %v\tExpected %v\n", v.desc, i+1, output, expected)
+		}
+
+		if err != nil {
+			log.Printf("desc : %v ,[TEST%d] Got %v\n", v.desc, i+1, err)
+		}
+	}
+}
+
 // TestDeleteAuthor function is to test delete method
 func TestDeleteAuthor(t *testing.T) {
 	testcases := []struct {
 		desc        string
 		id          string
+		query       string
+		ifMatch     string
+		current     int
+		version     int
+		policy      models.DeletePolicy
 		statusCode  int
 		rowAffected int
 		err         error
 	}{
-		{desc: "valid case", id: "1", statusCode: http.StatusNoContent, rowAffected: 1},
-		{desc: "error from svc", id: "-11", statusCode: http.StatusBadRequest},
+		{desc: "valid case", id: "1", policy: models.DeletePolicy{Books: models.DeleteReject},
+			statusCode: http.StatusNoContent, rowAffected: 1},
+		{desc: "cascade books", id: "2", query: "books=cascade", policy: models.DeletePolicy{Books: models.DeleteCascade},
+			statusCode: http.StatusNoContent, rowAffected: 1},
+		{desc: "reassign books", id: "3", query: "books=reassign&reassignTo=4",
+			policy: models.DeletePolicy{Books: models.DeleteReassign, ReassignTo: 4}, statusCode: http.StatusNoContent,
+			rowAffected: 1},
+		{desc: "if-match", id: "6", ifMatch: `"2"`, version: 2, policy: models.DeletePolicy{Books: models.DeleteReject},
+			statusCode: http.StatusNoContent, rowAffected: 1},
+		{desc: "if-match list", id: "8", ifMatch: `"2", "3"`, current: 2, version: 2,
+			policy: models.DeletePolicy{Books: models.DeleteReject}, statusCode: http.StatusNoContent, rowAffected: 1},
+		{desc: "unmatched if-match", id: "7", ifMatch: `"two"`, statusCode: http.StatusPreconditionFailed},
+		{desc: "invalid if-match", id: "7", ifMatch: "two", statusCode: http.StatusBadRequest},
+		{desc: "invalid reassignTo", id: "5", query: "books=reassign&reassignTo=abc", statusCode: http.StatusBadRequest},
+		{desc: "error from svc", id: "-11", policy: models.DeletePolicy{Books: models.DeleteReject},
+			statusCode: http.StatusBadRequest},
 		{desc: "missing params", id: "", statusCode: http.StatusBadRequest},
 		{desc: "error in strconv", id: "abc", statusCode: http.StatusBadRequest},
 	}
 
 	ctr := gomock.NewController(t)
 	mockAuthor := service.NewMockAuthor(ctr)
-	delivery := New(mockAuthor)
+	delivery := New(mockAuthor, service.NewMockBook(ctr))
 	k := gofr.New()
 
 	for i, v := range testcases {
-		params := url.Values{}
-		params.Add("bookId", v.id)
-
-		r := httptest.NewRequest(http.MethodPost, "/author/{id}"+v.id, nil)
+		r := httptest.NewRequest(http.MethodDelete, "/author/"+v.id+"?"+v.query, nil)
 		w := httptest.NewRecorder()
 
 		r = mux.SetURLVars(r, map[string]string{"id": v.id})
+		r.Header.Set("If-Match", v.ifMatch)
 
 		req := request.NewHTTPRequest(r)
 		resp := responder.NewContextualResponder(w, r)
@@ -174,7 +438,8 @@ func TestDeleteAuthor(t *testing.T) {
 			log.Printf("error in converting string : %v", err2)
 		}
 
-		mockAuthor.EXPECT().Delete(ctx, id).Return(v.rowAffected, v.err).AnyTimes()
+		mockAuthor.EXPECT().GetByID(ctx, id).Return(models.Author{AuthID: id, Version: v.current}, nil).AnyTimes()
+		mockAuthor.EXPECT().Delete(ctx, id, v.policy, v.version).Return(v.rowAffected, v.err).AnyTimes()
 
 		rowAffected, err := delivery.Delete(ctx)
 
@@ -1,12 +1,15 @@
 package book
 
 import (
-	"developer.zopsmart.com/go/gofr/pkg/errors"
 	"developer.zopsmart.com/go/gofr/pkg/gofr"
+	"developer.zopsmart.com/go/gofr/pkg/gofr/types"
 
 	"strconv"
+	"strings"
 
+	"mytest/delivery"
 	"mytest/models"
+	"mytest/models/errors"
 	"mytest/service"
 )
 
@@ -26,16 +29,35 @@ func (d Delivery) Create(c *gofr.Context) (interface{}, error) {
 		return models.Book{}, err
 	}
 
-	return d.service.Post(c, &book)
+	return withETag(d.service.Post(c, &book))
 }
 
-// GetAll method is get all details of Books
+// GetAll method is get a page of Books along with the pagination details
 func (d Delivery) GetAll(c *gofr.Context) (interface{}, error) {
-	title := c.Param("title")
-	includeAuthor := c.Param("includeAuthor")
+	includeAuthor := getIncludeAuthor(c)
 
-	// Getting all books
-	return d.service.GetAll(c, title, includeAuthor)
+	filter, err := getFilter(c)
+	if err != nil {
+		return []models.Book{}, err
+	}
+
+	sort, err := getSort(c)
+	if err != nil {
+		return []models.Book{}, err
+	}
+
+	page, err := delivery.GetPage(c)
+	if err != nil {
+		return []models.Book{}, err
+	}
+
+	// Getting a page of books
+	books, meta, err := d.service.GetAll(c, filter, includeAuthor, sort, page)
+	if err != nil {
+		return []models.Book{}, err
+	}
+
+	return types.Response{Data: books, Meta: meta}, nil
 }
 
 // GetByID method is get the book by its id
@@ -43,28 +65,44 @@ func (d Delivery) GetByID(c *gofr.Context) (interface{}, error) {
 	id := c.PathParam("id")
 
 	if id == "" {
-		return models.Book{}, errors.MissingParam{Param: []string{id}}
+		return models.Book{}, errors.InvalidParam{Code: errors.CodeMissingParam, Params: []string{"id"}}
 	}
 
 	id2, err := strconv.Atoi(id)
 	if err != nil {
-		return models.Book{}, errors.InvalidParam{Param: []string{id}}
+		return models.Book{}, errors.InvalidParam{Code: errors.CodeInvalidParam, Params: []string{"id"}}
+	}
+
+	return withETag(d.service.GetByID(c, id2, getIncludeAuthor(c)))
+}
+
+// GetByISBN method is get the book by its ISBN-10 or ISBN-13
+func (d Delivery) GetByISBN(c *gofr.Context) (interface{}, error) {
+	isbn := c.PathParam("isbn")
+
+	if isbn == "" {
+		return models.Book{}, errors.InvalidParam{Code: errors.CodeMissingParam, Params: []string{"isbn"}}
 	}
 
-	return d.service.GetByID(c, id2)
+	return withETag(d.service.GetByISBN(c, isbn, getIncludeAuthor(c)))
 }
 
-// Update method is to update details of Book
+// Update method is to update details of Book, only if it is still at the version in If-Match when given
 func (d Delivery) Update(c *gofr.Context) (interface{}, error) {
 	id := c.PathParam("id")
 
 	if id == "" {
-		return models.Book{}, errors.MissingParam{Param: []string{id}}
+		return models.Book{}, errors.InvalidParam{Code: errors.CodeMissingParam, Params: []string{"id"}}
 	}
 
 	id2, err := strconv.Atoi(id)
 	if err != nil {
-		return models.Book{}, errors.InvalidParam{Param: []string{id}}
+		return models.Book{}, errors.InvalidParam{Code: errors.CodeInvalidParam, Params: []string{"id"}}
+	}
+
+	version, err := delivery.GetVersion(c, "Book", id2, d.currentVersion)
+	if err != nil {
+		return models.Book{}, err
 	}
 
 	var book models.Book
@@ -73,21 +111,155 @@ func (d Delivery) Update(c *gofr.Context) (interface{}, error) {
 		return models.Book{}, err
 	}
 
-	return d.service.Update(c, id2, &book)
+	return withETag(d.service.Update(c, id2, &book, version))
 }
 
-// Delete method is to delete details of Book by its id
+// Patch method is to partially update the Book with a JSON merge patch, If-Match is honoured as in Update
+func (d Delivery) Patch(c *gofr.Context) (interface{}, error) {
+	id := c.PathParam("id")
+
+	if id == "" {
+		return models.Book{}, errors.InvalidParam{Code: errors.CodeMissingParam, Params: []string{"id"}}
+	}
+
+	id2, err := strconv.Atoi(id)
+	if err != nil {
+		return models.Book{}, errors.InvalidParam{Code: errors.CodeInvalidParam, Params: []string{"id"}}
+	}
+
+	version, err := delivery.GetVersion(c, "Book", id2, d.currentVersion)
+	if err != nil {
+		return models.Book{}, err
+	}
+
+	var patch models.BookPatch
+
+	if err := delivery.BindMergePatch(c, &patch); err != nil {
+		return models.Book{}, err
+	}
+
+	return withETag(d.service.Patch(c, id2, patch, version))
+}
+
+// Delete method is to delete details of Book by its id, If-Match is honoured as in Update
 func (d Delivery) Delete(c *gofr.Context) (interface{}, error) {
 	id := c.PathParam("id")
 
 	if id == "" {
-		return 0, errors.MissingParam{Param: []string{id}}
+		return 0, errors.InvalidParam{Code: errors.CodeMissingParam, Params: []string{"id"}}
 	}
 
 	id2, err := strconv.Atoi(id)
 	if err != nil {
-		return 0, errors.InvalidParam{Param: []string{id}}
+		return 0, errors.InvalidParam{Code: errors.CodeInvalidParam, Params: []string{"id"}}
+	}
+
+	version, err := delivery.GetVersion(c, "Book", id2, d.currentVersion)
+	if err != nil {
+		return 0, err
+	}
+
+	return d.service.Delete(c, id2, version)
+}
+
+// currentVersion method is to read the version the book is at, to tell which version of an If-Match list is honoured
+func (d Delivery) currentVersion(c *gofr.Context, id int) (int, error) {
+	book, err := d.service.GetByID(c, id, "false")
+
+	return book.Version, err
+}
+
+// withETag method is to respond a book along with the ETag of its version
+func withETag(book models.Book, err error) (interface{}, error) {
+	if err != nil {
+		return models.Book{}, err
+	}
+
+	return delivery.WithETag(book, book.Version), nil
+}
+
+// getIncludeAuthor method is to read whether the author is to be embedded,
+// either by includeAuthor=true or by expand=author
+func getIncludeAuthor(c *gofr.Context) string {
+	if c.Param("includeAuthor") == "true" {
+		return "true"
+	}
+
+	for _, e := range strings.Split(c.Param("expand"), ",") {
+		if strings.TrimSpace(e) == "author" {
+			return "true"
+		}
+	}
+
+	return c.Param("includeAuthor")
+}
+
+// getFilter method is to read the book filter query params
+func getFilter(c *gofr.Context) (models.BookFilter, error) {
+	filter := models.BookFilter{
+		Title:       c.Param("title"),
+		TitleMatch:  c.Param("titleMatch"),
+		Publication: c.Param("publication"),
+	}
+
+	if authorID := c.Param("authorId"); authorID != "" {
+		id, err := strconv.Atoi(authorID)
+		if err != nil {
+			return models.BookFilter{}, errors.InvalidParam{Code: errors.CodeInvalidParam, Params: []string{"authorId"}}
+		}
+
+		filter.AuthorID = id
+	}
+
+	var err error
+
+	if filter.PublishedAfter, err = getDate(c, "publishedAfter"); err != nil {
+		return models.BookFilter{}, err
+	}
+
+	if filter.PublishedBefore, err = getDate(c, "publishedBefore"); err != nil {
+		return models.BookFilter{}, err
+	}
+
+	return filter, nil
+}
+
+// getDate method is to read an optional date query param, in yyyy-mm-dd or dd/mm/yyyy
+func getDate(c *gofr.Context, param string) (models.Date, error) {
+	value := c.Param(param)
+	if value == "" {
+		return models.Date{}, nil
+	}
+
+	date, err := models.ParseDate(value)
+	if err != nil {
+		return models.Date{}, errors.InvalidParam{Code: errors.CodeInvalidParam, Params: []string{param}}
+	}
+
+	return date, nil
+}
+
+// getSort method is to read the sort query param, e.g. sort=title,-publishedDate
+func getSort(c *gofr.Context) ([]models.SortField, error) {
+	param := c.Param("sort")
+	if param == "" {
+		return nil, nil
+	}
+
+	fields := strings.Split(param, ",")
+	sort := make([]models.SortField, 0, len(fields))
+
+	for _, f := range fields {
+		f = strings.TrimSpace(f)
+		desc := strings.HasPrefix(f, "-")
+		f = strings.TrimPrefix(f, "-")
+
+		if f == "" {
+			return nil, errors.InvalidParam{Code: errors.CodeInvalidParam, Params: []string{"sort"}}
+		}
+
+		sort = append(sort, models.SortField{Field: f, Desc: desc})
 	}
 
-	return d.service.Delete(c, id2)
+	return sort, nil
 }
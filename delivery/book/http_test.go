@@ -6,6 +6,7 @@ import (
 	"developer.zopsmart.com/go/gofr/pkg/gofr"
 	"developer.zopsmart.com/go/gofr/pkg/gofr/request"
 	"developer.zopsmart.com/go/gofr/pkg/gofr/responder"
+	"developer.zopsmart.com/go/gofr/pkg/gofr/types"
 	"encoding/json"
 	"log"
 	"net/http"
@@ -18,10 +19,16 @@ import (
 	"github.com/golang/mock/gomock"
 	"github.com/gorilla/mux"
 
-	"Three-Layer-Architecture/models"
-	"Three-Layer-Architecture/service"
+	"mytest/models"
+	"mytest/service"
 )
 
+// etagged is the response of a handler returning the book along with the ETag of its version
+func etagged(book models.Book) types.RawWithOptions {
+	return types.RawWithOptions{Data: types.Response{Data: book}, ContentType: "application/json",
+		Header: map[string]string{"ETag": `"` + strconv.Itoa(book.Version) + `"`}}
+}
+
 // TestPostBook function is to test Post book method for posting books
 func TestPostBook(t *testing.T) {
 	testcase := []struct {
@@ -32,12 +39,12 @@ func TestPostBook(t *testing.T) {
 		err        error
 	}{
 		{desc: "valid details ", req: &models.Book{BookID: 1, AuthorID: 1,
-			Title: "2 States", Publication: "Scholastic", PublishedDate: "16/03/2016"},
+			Title: "2 States", Publication: "Scholastic", PublishedDate: models.NewDate(2016, 3, 16)},
 			resp: models.Book{BookID: 1, AuthorID: 1, Auth: models.Author{AuthID: 1, FirstName: "Chetan",
-				LastName: "Bhagat", Dob: "06/04/2001", PenName: "Chetan"}},
+				LastName: "Bhagat", Dob: models.NewDate(2001, 4, 6), PenName: "Chetan"}, Version: 1},
 			statusCode: http.StatusOK, err: nil},
 		{desc: "error from svc", req: &models.Book{BookID: -11, AuthorID: 1,
-			Title: "2 States", Publication: "Scholastic", PublishedDate: "16/03/2016"},
+			Title: "2 States", Publication: "Scholastic", PublishedDate: models.NewDate(2016, 3, 16)},
 			statusCode: http.StatusBadRequest, err: nil, resp: models.Book{}},
 		{desc: "error in bind", req: &[]models.Book{}, resp: models.Book{},
 			statusCode: http.StatusBadRequest, err: nil},
@@ -66,8 +73,13 @@ func TestPostBook(t *testing.T) {
 
 		book, err2 := delivery.Create(ctx)
 
-		if !reflect.DeepEqual(book, v.resp) {
-			t.Errorf("desc : %v ,[TEST%d]Failed. Got %v\tExpected %v\n", v.desc, i+1, book, v.resp)
+		expected := interface{}(v.resp)
+		if err2 == nil {
+			expected = etagged(v.resp)
+		}
+
+		if !reflect.DeepEqual(book, expected) {
+			t.Errorf("desc : %v ,[TEST%d]Failed. Got %v\tExpected %v\n", v.desc, i+1, book, expected)
 		}
 
 		if err2 != nil {
@@ -78,19 +90,80 @@ func TestPostBook(t *testing.T) {
 
 // TestGetAllBooks function is to test GetAll method for fetching details of books
 func TestGetAllBooks(t *testing.T) {
+	books := []models.Book{{BookID: 1, AuthorID: 1, Auth: models.Author{AuthID: 1, FirstName: "Chetan", LastName: "Bhagat",
+		Dob: models.NewDate(2001, 4, 6), PenName: "Chetan"}, Title: "States", Publication: "Scholastic",
+		PublishedDate: models.NewDate(2016, 3, 16)}}
+
 	testcases := []struct {
 		desc          string
-		title         string
+		query         string
+		filter        models.BookFilter
 		includeAuthor string
-		output        []models.Book
+		sort          []models.SortField
+		page          models.Page
+		books         []models.Book
+		meta          models.PageMeta
+		output        interface{}
 		statusCode    int
 		err           error
 	}{
 		{
-			desc: "valid details", output: []models.Book{
-				{BookID: 1, AuthorID: 1, Auth: models.Author{AuthID: 1, FirstName: "Chetan", LastName: "Bhagat", Dob: "06/04/2001", PenName: "Chetan"},
-					Title: "States", Publication: "Scholastic", PublishedDate: "16/03/2016"}}, statusCode: http.StatusOK,
-			title: "", includeAuthor: "", err: nil,
+			desc: "valid details", query: "title=&includeAuthor=", books: books, meta: models.PageMeta{Total: 1},
+			output: types.Response{Data: books, Meta: models.PageMeta{Total: 1}}, statusCode: http.StatusOK,
+		},
+		{
+			desc: "pagination params", query: "limit=1&offset=2&cursor=abc", page: models.Page{Limit: 1, Offset: 2, Cursor: "abc"},
+			books: books, meta: models.PageMeta{NextCursor: "next", Total: 5},
+			output: types.Response{Data: books, Meta: models.PageMeta{NextCursor: "next", Total: 5}}, statusCode: http.StatusOK,
+		},
+		{
+			desc: "filter params", query: "title=Sta&titleMatch=prefix&authorId=1&publication=Penguin&" +
+				"publishedAfter=01/01/2010&publishedBefore=31/12/2020",
+			filter: models.BookFilter{Title: "Sta", TitleMatch: "prefix", AuthorID: 1, Publication: "Penguin",
+				PublishedAfter: models.NewDate(2010, 1, 1), PublishedBefore: models.NewDate(2020, 12, 31)},
+			books: books, meta: models.PageMeta{Total: 1},
+			output: types.Response{Data: books, Meta: models.PageMeta{Total: 1}}, statusCode: http.StatusOK,
+		},
+		{
+			desc: "iso filter dates", query: "publishedAfter=2010-01-01&publishedBefore=2020-12-31",
+			filter: models.BookFilter{PublishedAfter: models.NewDate(2010, 1, 1),
+				PublishedBefore: models.NewDate(2020, 12, 31)},
+			books: books, meta: models.PageMeta{Total: 1},
+			output: types.Response{Data: books, Meta: models.PageMeta{Total: 1}}, statusCode: http.StatusOK,
+		},
+		{
+			desc: "invalid publishedAfter", query: "publishedAfter=31/02/2010", output: []models.Book{},
+			statusCode: http.StatusBadRequest,
+		},
+		{
+			desc: "invalid publishedBefore", query: "publishedBefore=2020-13-01", output: []models.Book{},
+			statusCode: http.StatusBadRequest,
+		},
+		{
+			desc: "expand author", query: "expand=author", includeAuthor: "true", books: books,
+			meta: models.PageMeta{Total: 1}, output: types.Response{Data: books, Meta: models.PageMeta{Total: 1}},
+			statusCode: http.StatusOK,
+		},
+		{
+			desc: "sort params", query: "sort=title,-publishedDate", sort: []models.SortField{{Field: "title"},
+				{Field: "publishedDate", Desc: true}}, books: books, meta: models.PageMeta{Total: 1},
+			output: types.Response{Data: books, Meta: models.PageMeta{Total: 1}}, statusCode: http.StatusOK,
+		},
+		{
+			desc: "empty sort field", query: "sort=title,,-", output: []models.Book{}, statusCode: http.StatusBadRequest,
+		},
+		{
+			desc: "invalid authorId", query: "authorId=abc", output: []models.Book{}, statusCode: http.StatusBadRequest,
+		},
+		{
+			desc: "invalid limit", query: "limit=abc", output: []models.Book{}, statusCode: http.StatusBadRequest,
+		},
+		{
+			desc: "invalid offset", query: "offset=abc", output: []models.Book{}, statusCode: http.StatusBadRequest,
+		},
+		{
+			desc: "error from svc", query: "title=Village", filter: models.BookFilter{Title: "Village"}, output: []models.Book{},
+			statusCode: http.StatusInternalServerError, err: errors.Error("error from svc"),
 		},
 	}
 
@@ -100,7 +173,7 @@ func TestGetAllBooks(t *testing.T) {
 	k := gofr.New()
 
 	for i, v := range testcases {
-		r := httptest.NewRequest(http.MethodGet, "/books?"+"title="+v.title+"&"+"includeAuthor="+v.includeAuthor, nil)
+		r := httptest.NewRequest(http.MethodGet, "/books?"+v.query, nil)
 
 		w := httptest.NewRecorder()
 
@@ -109,7 +182,7 @@ func TestGetAllBooks(t *testing.T) {
 
 		ctx := gofr.NewContext(resp, req, k)
 
-		mockBook.EXPECT().GetAll(ctx, v.title, v.includeAuthor).Return(v.output, v.err).AnyTimes()
+		mockBook.EXPECT().GetAll(ctx, v.filter, v.includeAuthor, v.sort, v.page).Return(v.books, v.meta, v.err).AnyTimes()
 
 		output, err := delivery.GetAll(ctx)
 
@@ -126,14 +199,27 @@ func TestGetAllBooks(t *testing.T) {
 // TestGetBook function is to test GetByID method for fetching a book
 func TestGetBook(t *testing.T) {
 	testcases := []struct {
-		desc       string
-		id         string
-		resp       models.Book
-		statusCode int
-		err        error
+		desc          string
+		id            string
+		query         string
+		includeAuthor string
+		resp          models.Book
+		statusCode    int
+		err           error
 	}{
 		{desc: "valid details", id: "1", resp: models.Book{BookID: 1, AuthorID: 1,
-			Title: "States", Publication: "Scholastic", PublishedDate: "16/03/2016"}, statusCode: http.StatusOK, err: nil},
+			Title: "States", Publication: "Scholastic", PublishedDate: models.NewDate(2016, 3, 16), Version: 2},
+			statusCode: http.StatusOK, err: nil},
+		{desc: "include author", id: "2", query: "includeAuthor=true", includeAuthor: "true", resp: models.Book{BookID: 2,
+			AuthorID: 1, Auth: models.Author{AuthID: 1, FirstName: "Chetan", LastName: "Bhagat",
+				Dob: models.NewDate(2001, 4, 6), PenName: "Chetan"}, Title: "States", Publication: "Scholastic",
+			PublishedDate: models.NewDate(2016, 3, 16)},
+			statusCode: http.StatusOK},
+		{desc: "expand author", id: "3", query: "expand=author", includeAuthor: "true", resp: models.Book{BookID: 3,
+			AuthorID: 1, Auth: models.Author{AuthID: 1, FirstName: "Chetan", LastName: "Bhagat",
+				Dob: models.NewDate(2001, 4, 6), PenName: "Chetan"}, Title: "States", Publication: "Scholastic",
+			PublishedDate: models.NewDate(2016, 3, 16)},
+			statusCode: http.StatusOK},
 		{desc: "missing param", id: "", resp: models.Book{}, statusCode: http.StatusBadRequest,
 			err: errors.Error("missing param")},
 		{desc: "invalid param", id: "abc", resp: models.Book{}, statusCode: http.StatusBadRequest,
@@ -146,10 +232,7 @@ func TestGetBook(t *testing.T) {
 	k := gofr.New()
 
 	for i, v := range testcases {
-		params := url.Values{}
-		params.Add("bookId", v.id)
-
-		r := httptest.NewRequest(http.MethodGet, "/books?"+params.Encode(), nil)
+		r := httptest.NewRequest(http.MethodGet, "/book/"+v.id+"?"+v.query, nil)
 
 		w := httptest.NewRecorder()
 
@@ -165,12 +248,17 @@ func TestGetBook(t *testing.T) {
 			log.Printf("error in converting string : %v", err)
 		}
 
-		mockBook.EXPECT().GetByID(ctx, id2).Return(v.resp, v.err).AnyTimes()
+		mockBook.EXPECT().GetByID(ctx, id2, v.includeAuthor).Return(v.resp, v.err).AnyTimes()
 
 		book, err2 := delivery.GetByID(ctx)
 
-		if !reflect.DeepEqual(book, v.resp) {
-			t.Errorf("desc : %v ,[TEST%d]Failed. Got %v\tExpected %v\n", v.desc, i+1, book, v.resp)
+		expected := interface{}(v.resp)
+		if err2 == nil {
+			expected = etagged(v.resp)
+		}
+
+		if !reflect.DeepEqual(book, expected) {
+			t.Errorf("desc : %v ,[TEST%d]Failed. Got %v\tExpected %v\n", v.desc, i+1, book, expected)
 		}
 
 		if err2 != nil {
@@ -179,24 +267,145 @@ func TestGetBook(t *testing.T) {
 	}
 }
 
+// TestGetBookBody function is to test the body a book is serialized to, with and without its author
+func TestGetBookBody(t *testing.T) {
+	book := models.Book{BookID: 1, AuthorID: 1, Title: "States", Publication: "Scholastic",
+		PublishedDate: models.NewDate(2016, 3, 16), Version: 2}
+	withAuthor := book
+	withAuthor.Auth = models.Author{AuthID: 1, FirstName: "Chetan", LastName: "Bhagat",
+		Dob: models.NewDate(2001, 4, 6), PenName: "Chetan"}
+
+	testcases := []struct {
+		desc          string
+		query         string
+		includeAuthor string
+		resp          models.Book
+		body          string
+	}{
+		{desc: "without author", resp: book, body: `{"data":{"bookID":1,"authID":1,` +
+			`"auth":{},"title":"States","publication":"Scholastic","publishedDate":"2016-03-16"}}`},
+		{desc: "with author", query: "includeAuthor=true", includeAuthor: "true", resp: withAuthor,
+			body: `{"data":{"bookID":1,"authID":1,"auth":{"authID":1,"firstName":"Chetan","lastName":"Bhagat",` +
+				`"dob":"2001-04-06","penName":"Chetan"},"title":"States","publication":"Scholastic",` +
+				`"publishedDate":"2016-03-16"}}`},
+	}
+
+	ctr := gomock.NewController(t)
+	mockBook := service.NewMockBook(ctr)
+	delivery := New(mockBook)
+	k := gofr.New()
+
+	for i, v := range testcases {
+		r := httptest.NewRequest(http.MethodGet, "/book/1?"+v.query, nil)
+		r = mux.SetURLVars(r, map[string]string{"id": "1"})
+
+		ctx := gofr.NewContext(responder.NewContextualResponder(httptest.NewRecorder(), r),
+			request.NewHTTPRequest(r), k)
+
+		mockBook.EXPECT().GetByID(ctx, 1, v.includeAuthor).Return(v.resp, nil)
+
+		resp, err := delivery.GetByID(ctx)
+		if err != nil {
+			t.Errorf("desc : %v ,[TEST%d]Failed. Got %v\tExpected %v\n", v.desc, i+1, err, nil)
+
+			continue
+		}
+
+		body, err := json.Marshal(resp.(types.RawWithOptions).Data)
+		if err != nil {
+			log.Printf("Not able to marshal : %v", err)
+		}
+
+		if string(body) != v.body {
+			t.Errorf("desc : %v ,[TEST%d]Failed. Got %v\tExpected %v\n", v.desc, i+1, string(body), v.body)
+		}
+	}
+}
+
+// TestGetBookByISBN function is to test GetByISBN method for fetching a book by its isbn
+func TestGetBookByISBN(t *testing.T) {
+	book := models.Book{BookID: 1, AuthorID: 1, Title: "States", Publication: "Scholastic",
+		PublishedDate: models.NewDate(2016, 3, 16), ISBN: "9780306406157"}
+
+	testcases := []struct {
+		desc          string
+		isbn          string
+		query         string
+		includeAuthor string
+		call          bool
+		resp          models.Book
+	}{
+		{desc: "valid details", isbn: "0-306-40615-2", call: true, resp: book},
+		{desc: "include author", isbn: "9780306406157", query: "includeAuthor=true", includeAuthor: "true", call: true,
+			resp: book},
+		{desc: "missing param", isbn: ""},
+	}
+
+	ctr := gomock.NewController(t)
+	mockBook := service.NewMockBook(ctr)
+	delivery := New(mockBook)
+	k := gofr.New()
+
+	for i, v := range testcases {
+		r := httptest.NewRequest(http.MethodGet, "/book/isbn/"+v.isbn+"?"+v.query, nil)
+		w := httptest.NewRecorder()
+
+		r = mux.SetURLVars(r, map[string]string{"isbn": v.isbn})
+
+		ctx := gofr.NewContext(responder.NewContextualResponder(w, r), request.NewHTTPRequest(r), k)
+
+		if v.call {
+			mockBook.EXPECT().GetByISBN(ctx, v.isbn, v.includeAuthor).Return(v.resp, nil)
+		}
+
+		book, err := delivery.GetByISBN(ctx)
+
+		expected := interface{}(v.resp)
+		if v.call {
+			expected = etagged(v.resp)
+		}
+
+		if !reflect.DeepEqual(book, expected) {
+			t.Errorf("desc : %v ,[TEST%d]Failed. Got %v\tExpected %v\n", v.desc, i+1, book, expected)
+		}
+
+		if err != nil {
+			log.Printf("desc : %v ,[TEST%d] Got %v\n", v.desc, i+1, err)
+		}
+	}
+}
+
 // TestUpdateBook function is to test Put method for updating details of book
 func TestUpdateBook(t *testing.T) {
 	testcases := []struct {
 		desc       string
 		id         string
+		ifMatch    string
+		version    int
 		req        interface{}
 		resp       models.Book
 		statusCode int
 		err        error
 	}{
 		{desc: "valid", id: "1", req: &models.Book{BookID: 1, AuthorID: 1, Title: "300 Days", Publication: "Penguin",
-			PublishedDate: "17/03/2016"}, resp: models.Book{BookID: 1, AuthorID: 1, Title: "300 Days", Publication: "Penguin",
-			PublishedDate: "17/03/2016"}, statusCode: http.StatusOK, err: nil},
+			PublishedDate: models.NewDate(2016, 3, 17)}, resp: models.Book{BookID: 1, AuthorID: 1, Title: "300 Days",
+			Publication:   "Penguin",
+			PublishedDate: models.NewDate(2016, 3, 17)}, statusCode: http.StatusOK, err: nil},
+		{desc: "if-match", id: "5", ifMatch: `"3"`, version: 3, req: &models.Book{AuthorID: 1, Title: "300 Days",
+			Publication: "Penguin", PublishedDate: models.NewDate(2016, 3, 17)}, resp: models.Book{BookID: 5,
+			AuthorID: 1, Title: "300 Days", Publication: "Penguin", PublishedDate: models.NewDate(2016, 3, 17),
+			Version: 4}, statusCode: http.StatusOK},
+		{desc: "weak if-match", id: "6", ifMatch: `W/"3"`, req: &models.Book{AuthorID: 1}, resp: models.Book{},
+			statusCode: http.StatusPreconditionFailed},
+		{desc: "invalid if-match", id: "6", ifMatch: "3", req: &models.Book{AuthorID: 1}, resp: models.Book{},
+			statusCode: http.StatusBadRequest},
 		{desc: "missing param", id: "", req: &models.Book{BookID: 2, AuthorID: 1,
-			Title: "300 Days", Publication: "Penguin", PublishedDate: "17/03/2016"}, err: errors.Error("missing param"),
+			Title: "300 Days", Publication: "Penguin", PublishedDate: models.NewDate(2016, 3, 17)},
+			err:        errors.Error("missing param"),
 			statusCode: http.StatusBadRequest, resp: models.Book{}},
 		{desc: "invalid param", id: "abc", req: &models.Book{BookID: 3, AuthorID: 1,
-			Title: "300 Days", Publication: "Penguin", PublishedDate: "17/03/2016"}, resp: models.Book{}, statusCode: http.StatusBadRequest,
+			Title: "300 Days", Publication: "Penguin", PublishedDate: models.NewDate(2016, 3, 17)},
+			resp: models.Book{}, statusCode: http.StatusBadRequest,
 			err: errors.Error("invalid param")},
 		{desc: "error in bind", id: "11", req: &[]models.Book{}, resp: models.Book{}, statusCode: http.StatusBadRequest,
 			err: errors.Error("error in bind")},
@@ -221,6 +430,7 @@ func TestUpdateBook(t *testing.T) {
 		w := httptest.NewRecorder()
 
 		r = mux.SetURLVars(r, map[string]string{"id": v.id})
+		r.Header.Set("If-Match", v.ifMatch)
 
 		req := request.NewHTTPRequest(r)
 		resp := responder.NewContextualResponder(w, r)
@@ -232,12 +442,17 @@ func TestUpdateBook(t *testing.T) {
 			log.Printf("error in converting string to int : %v", err2)
 		}
 
-		mockBook.EXPECT().Update(ctx, id2, v.req).Return(v.resp, v.err).AnyTimes()
+		mockBook.EXPECT().Update(ctx, id2, v.req, v.version).Return(v.resp, v.err).AnyTimes()
 
 		book, err3 := delivery.Update(ctx)
 
-		if !reflect.DeepEqual(book, v.resp) {
-			t.Errorf("desc : %v ,[TEST%d]Failed. Got %v\tExpected %v\n", v.desc, i+1, book, v.resp)
+		expected := interface{}(v.resp)
+		if err3 == nil {
+			expected = etagged(v.resp)
+		}
+
+		if !reflect.DeepEqual(book, expected) {
+			t.Errorf("desc : %v ,[TEST%d]Failed. Got %v\tExpected %v\n", v.desc, i+1, book, expected)
 		}
 
 		if err3 != nil {
@@ -246,16 +461,91 @@ func TestUpdateBook(t *testing.T) {
 	}
 }
 
+// TestPatchBook function is to test partial update of a book with a JSON merge patch
+func TestPatchBook(t *testing.T) {
+	title := "300 Days"
+	noISBN := models.ISBN("")
+
+	testcases := []struct {
+		desc  string
+		id    string
+		body  string
+		patch models.BookPatch
+		resp  models.Book
+		err   error
+	}{
+		{desc: "valid", id: "1", body: `{"title":"300 Days"}`, patch: models.BookPatch{Title: &title},
+			resp: models.Book{BookID: 1, Title: "300 Days"}},
+		{desc: "empty patch", id: "2", body: `{}`, resp: models.Book{BookID: 2}},
+		{desc: "removed field", id: "3", body: `{"title":"300 Days","publication":null}`, resp: models.Book{}},
+		{desc: "removed isbn", id: "6", body: `{"isbn":null}`, patch: models.BookPatch{ISBN: &noISBN},
+			resp: models.Book{BookID: 6, Title: "300 Days"}},
+		{desc: "missing param", id: "", body: `{}`, resp: models.Book{}},
+		{desc: "invalid param", id: "abc", body: `{}`, resp: models.Book{}},
+		{desc: "error in bind", id: "4", body: `[]`, resp: models.Book{}},
+		{desc: "error from svc", id: "5", body: `{"title":"300 Days"}`, patch: models.BookPatch{Title: &title},
+			resp: models.Book{}, err: errors.Error("error in patch")},
+	}
+
+	ctr := gomock.NewController(t)
+	mockBook := service.NewMockBook(ctr)
+	delivery := New(mockBook)
+	k := gofr.New()
+
+	for i, v := range testcases {
+		r := httptest.NewRequest(http.MethodPatch, "/book/"+v.id, bytes.NewReader([]byte(v.body)))
+		w := httptest.NewRecorder()
+
+		r = mux.SetURLVars(r, map[string]string{"id": v.id})
+
+		req := request.NewHTTPRequest(r)
+		resp := responder.NewContextualResponder(w, r)
+
+		ctx := gofr.NewContext(resp, req, k)
+
+		id, err := strconv.Atoi(v.id)
+		if err != nil {
+			log.Printf("error in converting string : %v", err)
+		}
+
+		mockBook.EXPECT().Patch(ctx, id, v.patch, 0).Return(v.resp, v.err).AnyTimes()
+
+		output, err := delivery.Patch(ctx)
+
+		expected := interface{}(v.resp)
+		if err == nil {
+			expected = etagged(v.resp)
+		}
+
+		if !reflect.DeepEqual(output, expected) {
+			t.Errorf("desc : %v ,[TEST%d]Failed. Got %v\tExpected %v\n", v.desc, i+1, output, expected)
+		}
+
+		if err != nil {
+			log.Printf("desc : %v ,[TEST%d] Got %v\n", v.desc, i+1, err)
+		}
+	}
+}
+
 // TestDeleteBook function is to test delete method to remove any book
 func TestDeleteBook(t *testing.T) {
 	testcases := []struct {
 		desc        string
 		id          string
+		ifMatch     string
+		current     int
+		version     int
 		rowAffected int
 		statusCode  int
 		err         error
 	}{
 		{desc: "valid", id: "1", rowAffected: 1, statusCode: http.StatusOK, err: nil},
+		{desc: "if-match", id: "2", ifMatch: `"3"`, version: 3, rowAffected: 1, statusCode: http.StatusOK},
+		{desc: "any version", id: "3", ifMatch: "*", rowAffected: 1, statusCode: http.StatusOK},
+		{desc: "if-match list", id: "5", ifMatch: `"2", "3"`, current: 3, version: 3, rowAffected: 1,
+			statusCode: http.StatusOK},
+		{desc: "unmatched if-match", id: "4", ifMatch: `"v3"`, statusCode: http.StatusPreconditionFailed},
+		{desc: "invalid if-match", id: "4", ifMatch: `"3`, statusCode: http.StatusBadRequest},
 		{desc: "missing param", id: "", rowAffected: 0, err: errors.Error("missing param"), statusCode: http.StatusBadRequest},
 		{desc: "invalid param", id: "abc", rowAffected: 0, err: errors.Error("invalid param"), statusCode: http.StatusBadRequest},
 	}
@@ -274,6 +564,7 @@ func TestDeleteBook(t *testing.T) {
 		w := httptest.NewRecorder()
 
 		r = mux.SetURLVars(r, map[string]string{"id": v.id})
+		r.Header.Set("If-Match", v.ifMatch)
 
 		req := request.NewHTTPRequest(r)
 		resp := responder.NewContextualResponder(w, r)
@@ -285,7 +576,8 @@ func TestDeleteBook(t *testing.T) {
 			log.Printf("error in converting string to int : %v", err2)
 		}
 
-		mockBook.EXPECT().Delete(ctx, id2).Return(v.rowAffected, v.err).AnyTimes()
+		mockBook.EXPECT().GetByID(ctx, id2, "false").Return(models.Book{BookID: id2, Version: v.current}, nil).AnyTimes()
+		mockBook.EXPECT().Delete(ctx, id2, v.version).Return(v.rowAffected, v.err).AnyTimes()
 
 		rowAffected, err := delivery.Delete(ctx)
 
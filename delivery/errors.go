@@ -0,0 +1,46 @@
+package delivery
+
+import (
+	gofrErrors "developer.zopsmart.com/go/gofr/pkg/errors"
+	"developer.zopsmart.com/go/gofr/pkg/gofr"
+
+	"net/http"
+
+	"mytest/models/errors"
+)
+
+// Handler method is to wrap a handler so that the domain errors it returns are rendered with their
+// HTTP status and code
+func Handler(h gofr.Handler) gofr.Handler {
+	return func(c *gofr.Context) (interface{}, error) {
+		data, err := h(c)
+		if err != nil {
+			return data, Error(err)
+		}
+
+		return data, nil
+	}
+}
+
+// Error method is to map a domain error to the error response of its HTTP status, any other error
+// is returned as it is
+func Error(err error) error {
+	switch e := err.(type) {
+	case errors.InvalidParam:
+		return &gofrErrors.Response{StatusCode: http.StatusBadRequest, Code: e.Code, Reason: e.Error(), Detail: e.Params}
+	case errors.Validation:
+		return &gofrErrors.Response{StatusCode: http.StatusUnprocessableEntity, Code: e.Code, Reason: e.Error(),
+			Detail: e.Fields}
+	case errors.NotFound:
+		return &gofrErrors.Response{StatusCode: http.StatusNotFound, Code: errors.CodeNotFound, Reason: e.Error(),
+			ResourceID: e.ID}
+	case errors.Conflict:
+		return &gofrErrors.Response{StatusCode: http.StatusConflict, Code: e.Code, Reason: e.Error(),
+			ResourceID: e.ResourceID}
+	case errors.PreconditionFailed:
+		return &gofrErrors.Response{StatusCode: http.StatusPreconditionFailed, Code: errors.CodeVersionMismatch,
+			Reason: e.Error(), ResourceID: e.ID}
+	default:
+		return err
+	}
+}
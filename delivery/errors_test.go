@@ -0,0 +1,49 @@
+package delivery
+
+import (
+	gofrErrors "developer.zopsmart.com/go/gofr/pkg/errors"
+	"net/http"
+	"reflect"
+	"testing"
+
+	"mytest/models/errors"
+)
+
+// TestError function is to test mapping the domain errors to their HTTP status
+func TestError(t *testing.T) {
+	fields := []errors.FieldError{{Field: "title", Reason: errors.ReasonMissing},
+		{Field: "publication", Reason: errors.ReasonUnknownPublication}}
+
+	testcases := []struct {
+		desc string
+		err  error
+		resp error
+	}{
+		{desc: "invalid param", err: errors.InvalidParam{Code: errors.CodeInvalidParam, Params: []string{"id"}},
+			resp: &gofrErrors.Response{StatusCode: http.StatusBadRequest, Code: errors.CodeInvalidParam,
+				Reason: "invalid param: id", Detail: []string{"id"}}},
+		{desc: "missing param", err: errors.InvalidParam{Code: errors.CodeMissingParam, Params: []string{"id"}},
+			resp: &gofrErrors.Response{StatusCode: http.StatusBadRequest, Code: errors.CodeMissingParam,
+				Reason: "missing param: id", Detail: []string{"id"}}},
+		{desc: "validation", err: errors.Validation{Code: errors.CodeInvalidField, Fields: fields, Reason: "invalid book"},
+			resp: &gofrErrors.Response{StatusCode: http.StatusUnprocessableEntity, Code: errors.CodeInvalidField,
+				Reason: "invalid book: title missing, publication unknown publication", Detail: fields}},
+		{desc: "not found", err: errors.NotFound{Entity: "Book", ID: "3"}, resp: &gofrErrors.Response{
+			StatusCode: http.StatusNotFound, Code: errors.CodeNotFound, Reason: "Book 3 not found", ResourceID: "3"}},
+		{desc: "conflict", err: errors.Conflict{Code: errors.CodeAuthorHasBooks, Reason: "author 4 has 2 books",
+			ResourceID: "4"}, resp: &gofrErrors.Response{StatusCode: http.StatusConflict, Code: errors.CodeAuthorHasBooks,
+			Reason: "author 4 has 2 books", ResourceID: "4"}},
+		{desc: "precondition failed", err: errors.PreconditionFailed{Entity: "Book", ID: "5"},
+			resp: &gofrErrors.Response{StatusCode: http.StatusPreconditionFailed, Code: errors.CodeVersionMismatch,
+				Reason: "Book 5 has changed since the version in If-Match", ResourceID: "5"}},
+		{desc: "other error", err: gofrErrors.Error("error in db"), resp: gofrErrors.Error("error in db")},
+	}
+
+	for i, v := range testcases {
+		resp := Error(v.err)
+
+		if !reflect.DeepEqual(resp, v.resp) {
+			t.Errorf("desc : %v ,[TEST%d]Failed. Got %v\tExpected %v\n", v.desc, i+1, resp, v.resp)
+		}
+	}
+}
@@ -0,0 +1,108 @@
+package delivery
+
+import (
+	"developer.zopsmart.com/go/gofr/pkg/gofr"
+	"developer.zopsmart.com/go/gofr/pkg/gofr/types"
+
+	"strconv"
+	"strings"
+
+	"mytest/models/errors"
+)
+
+// ETag method is to get the strong entity tag of a version of an entity
+func ETag(version int) string {
+	return `"` + strconv.Itoa(version) + `"`
+}
+
+// WithETag method is to respond the entity in the usual envelope along with the ETag header of its version
+func WithETag(data interface{}, version int) types.RawWithOptions {
+	return types.RawWithOptions{Data: types.Response{Data: data}, ContentType: "application/json",
+		Header: map[string]string{"ETag": ETag(version)}}
+}
+
+// GetVersion method is to read the version the If-Match header conditions a change of the entity on, 0 when
+// there is no header or it is * as the entity is required to exist anyway. The header is a list of entity tags
+// compared strongly, so weak tags and tags of no version never match and a list of only them fails the
+// precondition. When several versions are listed the current one is read to tell which of them is honoured.
+func GetVersion(c *gofr.Context, entity string, id int,
+	current func(c *gofr.Context, id int) (int, error)) (int, error) {
+	header := strings.TrimSpace(c.Header("If-Match"))
+	if header == "" || header == "*" {
+		return 0, nil
+	}
+
+	versions, err := parseIfMatch(header)
+	if err != nil {
+		return 0, err
+	}
+
+	errFailed := errors.PreconditionFailed{Entity: entity, ID: strconv.Itoa(id)}
+
+	switch len(versions) {
+	case 0:
+		return 0, errFailed
+	case 1:
+		return versions[0], nil
+	}
+
+	version, err := current(c, id)
+	if err != nil {
+		return 0, err
+	}
+
+	for _, v := range versions {
+		if v == version {
+			return version, nil
+		}
+	}
+
+	return 0, errFailed
+}
+
+// parseIfMatch is to read the distinct versions of the strong entity tags in an If-Match list, an opaque tag
+// may have a comma so the list is scanned tag by tag rather than split
+func parseIfMatch(header string) ([]int, error) {
+	errInvalid := errors.InvalidParam{Code: errors.CodeInvalidParam, Params: []string{"If-Match"}}
+
+	var versions []int
+
+	seen := make(map[int]bool)
+
+	for rest := header; ; {
+		// a list may have empty elements
+		rest = strings.TrimLeft(rest, " \t,")
+		if rest == "" {
+			return versions, nil
+		}
+
+		weak := strings.HasPrefix(rest, "W/")
+		if weak {
+			rest = rest[2:]
+		}
+
+		if !strings.HasPrefix(rest, `"`) {
+			return nil, errInvalid
+		}
+
+		end := strings.Index(rest[1:], `"`)
+		if end < 0 {
+			return nil, errInvalid
+		}
+
+		opaque := rest[1 : end+1]
+		rest = strings.TrimLeft(rest[end+2:], " \t")
+
+		if rest != "" && rest[0] != ',' {
+			return nil, errInvalid
+		}
+
+		version, err := strconv.Atoi(opaque)
+		if weak || err != nil || version <= 0 || seen[version] {
+			continue
+		}
+
+		seen[version] = true
+		versions = append(versions, version)
+	}
+}
@@ -0,0 +1,75 @@
+package delivery
+
+import (
+	gofrErrors "developer.zopsmart.com/go/gofr/pkg/errors"
+	"developer.zopsmart.com/go/gofr/pkg/gofr"
+	"developer.zopsmart.com/go/gofr/pkg/gofr/request"
+	"developer.zopsmart.com/go/gofr/pkg/gofr/responder"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"mytest/models/errors"
+)
+
+// TestGetVersion function is to test reading the version a change is conditioned on from If-Match
+func TestGetVersion(t *testing.T) {
+	errInvalid := errors.InvalidParam{Code: errors.CodeInvalidParam, Params: []string{"If-Match"}}
+	errFailed := errors.PreconditionFailed{Entity: "Book", ID: "5"}
+
+	testcases := []struct {
+		desc       string
+		ifMatch    string
+		current    int
+		currentErr error
+		version    int
+		err        error
+	}{
+		{desc: "no header", version: 0},
+		{desc: "any version", ifMatch: "*", version: 0},
+		{desc: "strong tag", ifMatch: `"3"`, version: 3},
+		{desc: "weak tag", ifMatch: `W/"3"`, err: errFailed},
+		{desc: "tag of no version", ifMatch: `"v3"`, err: errFailed},
+		{desc: "tag of version 0", ifMatch: `"0"`, err: errFailed},
+		{desc: "weak and strong tags", ifMatch: `W/"2", "3"`, version: 3},
+		{desc: "repeated tag", ifMatch: `"3", "3"`, version: 3},
+		{desc: "empty list elements", ifMatch: ` , "3",, `, version: 3},
+		{desc: "tag with a comma", ifMatch: `"a,b", "3"`, version: 3},
+		{desc: "list with the current version", ifMatch: `"3", "4"`, current: 4, version: 4},
+		{desc: "list without the current version", ifMatch: `"3", "4"`, current: 5, err: errFailed},
+		{desc: "error in reading the current version", ifMatch: `"3","4"`,
+			currentErr: errors.NotFound{Entity: "Book", ID: "5"}, err: errors.NotFound{Entity: "Book", ID: "5"}},
+		{desc: "unquoted tag", ifMatch: "3", err: errInvalid},
+		{desc: "unterminated tag", ifMatch: `"3`, err: errInvalid},
+		{desc: "tags without a comma", ifMatch: `"3" "4"`, err: errInvalid},
+		{desc: "any version in a list", ifMatch: `*, "3"`, err: errInvalid},
+	}
+
+	k := gofr.New()
+
+	for i, v := range testcases {
+		r := httptest.NewRequest(http.MethodPut, "/books/5", nil)
+		r.Header.Set("If-Match", v.ifMatch)
+
+		ctx := gofr.NewContext(responder.NewContextualResponder(httptest.NewRecorder(), r), request.NewHTTPRequest(r), k)
+
+		current := func(c *gofr.Context, id int) (int, error) {
+			if id != 5 {
+				return 0, gofrErrors.Error("unexpected id")
+			}
+
+			return v.current, v.currentErr
+		}
+
+		version, err := GetVersion(ctx, "Book", 5, current)
+
+		if version != v.version {
+			t.Errorf("desc : %v ,[TEST%d]Failed. Got %v\tExpected %v\n", v.desc, i+1, version, v.version)
+		}
+
+		if !reflect.DeepEqual(err, v.err) {
+			t.Errorf("desc : %v ,[TEST%d]Failed. Got %v\tExpected %v\n", v.desc, i+1, err, v.err)
+		}
+	}
+}
@@ -0,0 +1,36 @@
+package delivery
+
+import (
+	"developer.zopsmart.com/go/gofr/pkg/gofr"
+
+	"strconv"
+
+	"mytest/models"
+	"mytest/models/errors"
+)
+
+// GetPage method is to read the limit, offset and cursor query params of a listing
+func GetPage(c *gofr.Context) (models.Page, error) {
+	var (
+		page models.Page
+		err  error
+	)
+
+	if limit := c.Param("limit"); limit != "" {
+		page.Limit, err = strconv.Atoi(limit)
+		if err != nil {
+			return models.Page{}, errors.InvalidParam{Code: errors.CodeInvalidParam, Params: []string{"limit"}}
+		}
+	}
+
+	if offset := c.Param("offset"); offset != "" {
+		page.Offset, err = strconv.Atoi(offset)
+		if err != nil {
+			return models.Page{}, errors.InvalidParam{Code: errors.CodeInvalidParam, Params: []string{"offset"}}
+		}
+	}
+
+	page.Cursor = c.Param("cursor")
+
+	return page, nil
+}
@@ -0,0 +1,103 @@
+package delivery
+
+import (
+	"developer.zopsmart.com/go/gofr/pkg/gofr"
+
+	"encoding/json"
+	"reflect"
+	"sort"
+	"strings"
+
+	"mytest/models/errors"
+)
+
+// BindMergePatch method is to bind a JSON merge patch (RFC 7396) body into patch. A null member removes an
+// optional field of patch, which is bound as the zero value of the field, while a null member removing a
+// required field is rejected.
+func BindMergePatch(c *gofr.Context, patch interface{}) error {
+	var members map[string]json.RawMessage
+
+	if err := c.Bind(&members); err != nil {
+		return err
+	}
+
+	optional := optionalFields(patch)
+
+	var removed []errors.FieldError
+
+	for name, value := range members {
+		if string(value) != "null" {
+			continue
+		}
+
+		if zero, ok := optional[name]; ok {
+			members[name] = zero
+
+			continue
+		}
+
+		removed = append(removed, errors.FieldError{Field: name, Reason: errors.ReasonNotRemovable})
+	}
+
+	if len(removed) > 0 {
+		sort.Slice(removed, func(i, j int) bool { return removed[i].Field < removed[j].Field })
+
+		return errors.Validation{Code: errors.CodeInvalidField, Fields: removed, Reason: "invalid patch"}
+	}
+
+	body, err := json.Marshal(members)
+	if err != nil {
+		return err
+	}
+
+	return json.Unmarshal(body, patch)
+}
+
+// optionalFields is to get the JSON of the zero value of every optional field of the patch struct by its json
+// name, a field is optional when its validate tag does not have the required rule
+func optionalFields(patch interface{}) map[string]json.RawMessage {
+	typ := reflect.TypeOf(patch)
+	for typ.Kind() == reflect.Ptr {
+		typ = typ.Elem()
+	}
+
+	fields := make(map[string]json.RawMessage)
+
+	if typ.Kind() != reflect.Struct {
+		return fields
+	}
+
+	for i := 0; i < typ.NumField(); i++ {
+		f := typ.Field(i)
+
+		name := strings.Split(f.Tag.Get("json"), ",")[0]
+		if name == "" || name == "-" || hasRule(f.Tag.Get("validate"), "required") {
+			continue
+		}
+
+		fieldType := f.Type
+		if fieldType.Kind() == reflect.Ptr {
+			fieldType = fieldType.Elem()
+		}
+
+		zero, err := json.Marshal(reflect.Zero(fieldType).Interface())
+		if err != nil {
+			continue
+		}
+
+		fields[name] = zero
+	}
+
+	return fields
+}
+
+// hasRule is to check whether the comma separated rules of a validate tag have the given one
+func hasRule(tag, rule string) bool {
+	for _, r := range strings.Split(tag, ",") {
+		if r == rule {
+			return true
+		}
+	}
+
+	return false
+}
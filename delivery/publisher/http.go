@@ -0,0 +1,86 @@
+package publisher
+
+import (
+	"developer.zopsmart.com/go/gofr/pkg/gofr"
+	"strconv"
+
+	"mytest/models"
+	"mytest/models/errors"
+	"mytest/service"
+)
+
+type Delivery struct {
+	service service.Publisher
+}
+
+func New(publisher service.Publisher) Delivery {
+	return Delivery{service: publisher}
+}
+
+// Create method is to onboard a publisher
+func (d Delivery) Create(c *gofr.Context) (interface{}, error) {
+	var publisher models.Publisher
+
+	if err := c.Bind(&publisher); err != nil {
+		return models.Publisher{}, err
+	}
+
+	return d.service.Post(c, publisher)
+}
+
+// GetAll method is to get all the publishers
+func (d Delivery) GetAll(c *gofr.Context) (interface{}, error) {
+	return d.service.GetAll(c)
+}
+
+// GetByID method is to get the publisher by its id
+func (d Delivery) GetByID(c *gofr.Context) (interface{}, error) {
+	id, err := getID(c)
+	if err != nil {
+		return models.Publisher{}, err
+	}
+
+	return d.service.GetByID(c, id)
+}
+
+// Update method is to rename the publisher
+func (d Delivery) Update(c *gofr.Context) (interface{}, error) {
+	id, err := getID(c)
+	if err != nil {
+		return models.Publisher{}, err
+	}
+
+	var publisher models.Publisher
+
+	if err := c.Bind(&publisher); err != nil {
+		return models.Publisher{}, err
+	}
+
+	return d.service.Update(c, id, publisher)
+}
+
+// Delete method is to delete the publisher by its id
+func (d Delivery) Delete(c *gofr.Context) (interface{}, error) {
+	id, err := getID(c)
+	if err != nil {
+		return 0, err
+	}
+
+	return d.service.Delete(c, id)
+}
+
+// getID method is to read the id path param
+func getID(c *gofr.Context) (int, error) {
+	id := c.PathParam("id")
+
+	if id == "" {
+		return 0, errors.InvalidParam{Code: errors.CodeMissingParam, Params: []string{"id"}}
+	}
+
+	id2, err := strconv.Atoi(id)
+	if err != nil {
+		return 0, errors.InvalidParam{Code: errors.CodeInvalidParam, Params: []string{"id"}}
+	}
+
+	return id2, nil
+}
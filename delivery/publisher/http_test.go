@@ -0,0 +1,226 @@
+package publisher
+
+import (
+	"bytes"
+	"developer.zopsmart.com/go/gofr/pkg/gofr"
+	"developer.zopsmart.com/go/gofr/pkg/gofr/request"
+	"developer.zopsmart.com/go/gofr/pkg/gofr/responder"
+	"encoding/json"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/gorilla/mux"
+
+	"mytest/models"
+	"mytest/models/errors"
+	"mytest/service"
+)
+
+// TestCreatePublisher function is to test onboarding a publisher
+func TestCreatePublisher(t *testing.T) {
+	testcases := []struct {
+		desc string
+		req  interface{}
+		resp models.Publisher
+		err  error
+	}{
+		{desc: "valid", req: models.Publisher{Name: "Penguin"}, resp: models.Publisher{PublisherID: 1, Name: "Penguin"}},
+		{desc: "error in bind", req: "Penguin"},
+		{desc: "error from svc", req: models.Publisher{Name: "Penguin"}, err: errors.Conflict{
+			Code: errors.CodePublisherExists, Reason: "publisher Penguin already exists", ResourceID: "1"}},
+	}
+
+	ctr := gomock.NewController(t)
+	mockPublisher := service.NewMockPublisher(ctr)
+	delivery := New(mockPublisher)
+	k := gofr.New()
+
+	for i, v := range testcases {
+		body, err := json.Marshal(v.req)
+		if err != nil {
+			log.Printf("Not able to marshal : %v", err)
+		}
+
+		r := httptest.NewRequest(http.MethodPost, "/publisher", bytes.NewReader(body))
+		w := httptest.NewRecorder()
+
+		req := request.NewHTTPRequest(r)
+		resp := responder.NewContextualResponder(w, r)
+
+		ctx := gofr.NewContext(resp, req, k)
+
+		mockPublisher.EXPECT().Post(ctx, v.req).Return(v.resp, v.err).MaxTimes(1)
+
+		publisher, err := delivery.Create(ctx)
+
+		if !reflect.DeepEqual(publisher, v.resp) {
+			t.Errorf("desc : %v ,[TEST%d]Failed. Got %v\tExpected %v\n", v.desc, i+1, publisher, v.resp)
+		}
+
+		if err != nil {
+			log.Printf("desc : %v ,[TEST%d] Got %v\n", v.desc, i+1, err)
+		}
+	}
+}
+
+// TestGetAllPublishers function is to test listing the publishers
+func TestGetAllPublishers(t *testing.T) {
+	publishers := []models.Publisher{{PublisherID: 2, Name: "Arihant"}, {PublisherID: 1, Name: "Penguin"}}
+
+	ctr := gomock.NewController(t)
+	mockPublisher := service.NewMockPublisher(ctr)
+	delivery := New(mockPublisher)
+
+	r := httptest.NewRequest(http.MethodGet, "/publishers", nil)
+	w := httptest.NewRecorder()
+
+	ctx := gofr.NewContext(responder.NewContextualResponder(w, r), request.NewHTTPRequest(r), gofr.New())
+
+	mockPublisher.EXPECT().GetAll(ctx).Return(publishers, nil)
+
+	resp, err := delivery.GetAll(ctx)
+
+	if !reflect.DeepEqual(resp, publishers) || err != nil {
+		t.Errorf("desc : valid ,Failed. Got %v, %v\tExpected %v\n", resp, err, publishers)
+	}
+}
+
+// TestGetPublisher function is to test getting a publisher by its id
+func TestGetPublisher(t *testing.T) {
+	testcases := []struct {
+		desc string
+		id   string
+		call bool
+		resp models.Publisher
+		err  error
+	}{
+		{desc: "valid", id: "1", call: true, resp: models.Publisher{PublisherID: 1, Name: "Penguin"}},
+		{desc: "missing param", id: "", err: errors.InvalidParam{Code: errors.CodeMissingParam, Params: []string{"id"}}},
+		{desc: "invalid param", id: "abc", err: errors.InvalidParam{Code: errors.CodeInvalidParam, Params: []string{"id"}}},
+	}
+
+	ctr := gomock.NewController(t)
+	mockPublisher := service.NewMockPublisher(ctr)
+	delivery := New(mockPublisher)
+	k := gofr.New()
+
+	for i, v := range testcases {
+		r := httptest.NewRequest(http.MethodGet, "/publisher/"+v.id, nil)
+		w := httptest.NewRecorder()
+
+		r = mux.SetURLVars(r, map[string]string{"id": v.id})
+
+		ctx := gofr.NewContext(responder.NewContextualResponder(w, r), request.NewHTTPRequest(r), k)
+
+		if v.call {
+			mockPublisher.EXPECT().GetByID(ctx, v.resp.PublisherID).Return(v.resp, nil)
+		}
+
+		publisher, err := delivery.GetByID(ctx)
+
+		if !reflect.DeepEqual(publisher, v.resp) {
+			t.Errorf("desc : %v ,[TEST%d]Failed. Got %v\tExpected %v\n", v.desc, i+1, publisher, v.resp)
+		}
+
+		if !reflect.DeepEqual(err, v.err) {
+			t.Errorf("desc : %v ,[TEST%d]Failed. Got %v\tExpected %v\n", v.desc, i+1, err, v.err)
+		}
+	}
+}
+
+// TestUpdatePublisher function is to test renaming a publisher
+func TestUpdatePublisher(t *testing.T) {
+	testcases := []struct {
+		desc string
+		id   string
+		req  interface{}
+		call bool
+		resp models.Publisher
+	}{
+		{desc: "valid", id: "1", req: models.Publisher{Name: "Penguin Books"}, call: true,
+			resp: models.Publisher{PublisherID: 1, Name: "Penguin Books"}},
+		{desc: "invalid param", id: "abc", req: models.Publisher{Name: "Penguin Books"}},
+		{desc: "error in bind", id: "1", req: "Penguin Books"},
+	}
+
+	ctr := gomock.NewController(t)
+	mockPublisher := service.NewMockPublisher(ctr)
+	delivery := New(mockPublisher)
+	k := gofr.New()
+
+	for i, v := range testcases {
+		body, err := json.Marshal(v.req)
+		if err != nil {
+			log.Printf("Not able to marshal : %v", err)
+		}
+
+		r := httptest.NewRequest(http.MethodPut, "/publisher/"+v.id, bytes.NewReader(body))
+		w := httptest.NewRecorder()
+
+		r = mux.SetURLVars(r, map[string]string{"id": v.id})
+
+		ctx := gofr.NewContext(responder.NewContextualResponder(w, r), request.NewHTTPRequest(r), k)
+
+		if v.call {
+			mockPublisher.EXPECT().Update(ctx, 1, v.req).Return(v.resp, nil)
+		}
+
+		publisher, err := delivery.Update(ctx)
+
+		if !reflect.DeepEqual(publisher, v.resp) {
+			t.Errorf("desc : %v ,[TEST%d]Failed. Got %v\tExpected %v\n", v.desc, i+1, publisher, v.resp)
+		}
+
+		if err != nil {
+			log.Printf("desc : %v ,[TEST%d] Got %v\n", v.desc, i+1, err)
+		}
+	}
+}
+
+// TestDeletePublisher function is to test deleting a publisher
+func TestDeletePublisher(t *testing.T) {
+	testcases := []struct {
+		desc string
+		id   string
+		call bool
+		resp int
+		err  error
+	}{
+		{desc: "valid", id: "1", call: true, resp: 1},
+		{desc: "publisher has books", id: "3", call: true, err: errors.Conflict{Code: errors.CodePublisherHasBooks,
+			Reason: "publisher 3 has 4 books", ResourceID: "3"}},
+		{desc: "missing param", id: "", err: errors.InvalidParam{Code: errors.CodeMissingParam, Params: []string{"id"}}},
+	}
+
+	ctr := gomock.NewController(t)
+	mockPublisher := service.NewMockPublisher(ctr)
+	delivery := New(mockPublisher)
+	k := gofr.New()
+
+	for i, v := range testcases {
+		r := httptest.NewRequest(http.MethodDelete, "/publisher/"+v.id, nil)
+		w := httptest.NewRecorder()
+
+		r = mux.SetURLVars(r, map[string]string{"id": v.id})
+
+		ctx := gofr.NewContext(responder.NewContextualResponder(w, r), request.NewHTTPRequest(r), k)
+
+		if v.call {
+			mockPublisher.EXPECT().Delete(ctx, gomock.Any()).Return(v.resp, v.err)
+		}
+
+		resp, err := delivery.Delete(ctx)
+
+		if !reflect.DeepEqual(resp, v.resp) {
+			t.Errorf("desc : %v ,[TEST%d]Failed. Got %v\tExpected %v\n", v.desc, i+1, resp, v.resp)
+		}
+
+		if !reflect.DeepEqual(err, v.err) {
+			t.Errorf("desc : %v ,[TEST%d]Failed. Got %v\tExpected %v\n", v.desc, i+1, err, v.err)
+		}
+	}
+}
@@ -2,38 +2,135 @@ package main
 
 import (
 	"developer.zopsmart.com/go/gofr/pkg/gofr"
+	"fmt"
+	"os"
+	"strconv"
+	"time"
 
 	datastoreauthor "mytest/datastore/author"
 	datastorebook "mytest/datastore/book"
+	datastorepublisher "mytest/datastore/publisher"
+	"mytest/datastore/unitofwork"
+	"mytest/delivery"
 	deliveryauthor "mytest/delivery/author"
 	deliverybook "mytest/delivery/book"
+	deliverypublisher "mytest/delivery/publisher"
+	"mytest/migrations"
+	"mytest/models"
 	serviceauthor "mytest/service/author"
 	servicebook "mytest/service/book"
+	servicepublisher "mytest/service/publisher"
 )
 
 func main() {
-	authorDatastore := datastoreauthor.New()
-	authorService := serviceauthor.New(authorDatastore)
-	authorHandler := deliveryauthor.New(authorService)
+	r := gofr.New()
+
+	// `migrate up` applies the pending migrations and `migrate down [n]` reverts the last n, without serving
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		if err := migrate(r, os.Args[2:]); err != nil {
+			r.Logger.Fatalf("migrate: %v", err)
+		}
+
+		return
+	}
+
+	if r.Config.GetOrDefault("DB_MIGRATE_ON_START", "false") == "true" {
+		if err := migrate(r, nil); err != nil {
+			r.Logger.Fatalf("migrate: %v", err)
+		}
+	}
+
+	horizonDays, err := strconv.Atoi(r.Config.GetOrDefault("PUBLISHED_DATE_HORIZON_DAYS", "0"))
+	if err != nil || horizonDays < 0 {
+		r.Logger.Fatalf("invalid PUBLISHED_DATE_HORIZON_DAYS: %v", r.Config.Get("PUBLISHED_DATE_HORIZON_DAYS"))
+	}
 
+	duplicateRule := r.Config.GetOrDefault("BOOK_DUPLICATE_RULE", models.DuplicateISBN)
+	if duplicateRule != models.DuplicateISBN && duplicateRule != models.DuplicateTitle {
+		r.Logger.Fatalf("invalid BOOK_DUPLICATE_RULE: %v", duplicateRule)
+	}
+
+	authorDatastore := datastoreauthor.New()
 	bookDatastore := datastorebook.New()
-	bookService := servicebook.New(bookDatastore, authorDatastore)
+	publisherDatastore := datastorepublisher.New()
+
+	unitOfWork := unitofwork.New()
+
+	authorService := serviceauthor.New(authorDatastore, unitOfWork, time.Now)
+	bookService := servicebook.New(bookDatastore, authorDatastore, publisherDatastore, unitOfWork, time.Now,
+		horizonDays, duplicateRule)
+	publisherService := servicepublisher.New(publisherDatastore, unitOfWork)
+
+	authorHandler := deliveryauthor.New(authorService, bookService)
 	bookHandler := deliverybook.New(bookService)
+	publisherHandler := deliverypublisher.New(publisherService)
 
-	r := gofr.New()
+	// Every handler is wrapped so that domain errors get their HTTP status and code
 
 	// Author endpoint
-	r.POST("/author", authorHandler.Create)
-	r.PUT("/author/{id}", authorHandler.Update)
-	r.DELETE("/author/{id}", authorHandler.Delete)
+	r.POST("/author", delivery.Handler(authorHandler.Create))
+	r.GET("/authors", delivery.Handler(authorHandler.GetAll))
+	r.GET("/author/{id}", delivery.Handler(authorHandler.GetByID))
+	r.GET("/author/{id}/books", delivery.Handler(authorHandler.GetBooks))
+	r.PUT("/author/{id}", delivery.Handler(authorHandler.Update))
+	r.PATCH("/author/{id}", delivery.Handler(authorHandler.Patch))
+	r.DELETE("/author/{id}", delivery.Handler(authorHandler.Delete))
 
 	// Book endpoints
-	r.POST("/book", bookHandler.Create)
-	r.GET("/books", bookHandler.GetAll)
-	r.GET("/book/{id}", bookHandler.GetByID)
-	r.PUT("/book/{id}", bookHandler.Update)
-	r.DELETE("/book/{id}", bookHandler.Delete)
+	r.POST("/book", delivery.Handler(bookHandler.Create))
+	r.GET("/books", delivery.Handler(bookHandler.GetAll))
+	r.GET("/book/{id}", delivery.Handler(bookHandler.GetByID))
+	r.GET("/book/isbn/{isbn}", delivery.Handler(bookHandler.GetByISBN))
+	r.PUT("/book/{id}", delivery.Handler(bookHandler.Update))
+	r.PATCH("/book/{id}", delivery.Handler(bookHandler.Patch))
+	r.DELETE("/book/{id}", delivery.Handler(bookHandler.Delete))
+
+	// Publisher endpoints, the publication of a book must be one of the publishers
+	r.POST("/publisher", delivery.Handler(publisherHandler.Create))
+	r.GET("/publishers", delivery.Handler(publisherHandler.GetAll))
+	r.GET("/publisher/{id}", delivery.Handler(publisherHandler.GetByID))
+	r.PUT("/publisher/{id}", delivery.Handler(publisherHandler.Update))
+	r.DELETE("/publisher/{id}", delivery.Handler(publisherHandler.Delete))
 
 	r.Start()
 
 }
+
+// migrate is to move the schema of the database, up to the latest migration by default or down by the
+// given number of migrations, 1 by default
+func migrate(r *gofr.Gofr, args []string) error {
+	all, err := migrations.Load(migrations.Files)
+	if err != nil {
+		return err
+	}
+
+	migrator := migrations.New(r.DB().DB, all)
+
+	direction := "up"
+	if len(args) > 0 {
+		direction = args[0]
+	}
+
+	switch direction {
+	case "up":
+		count, err := migrator.Up()
+		r.Logger.Infof("applied %v migrations", count)
+
+		return err
+	case "down":
+		steps := 1
+
+		if len(args) > 1 {
+			if steps, err = strconv.Atoi(args[1]); err != nil || steps <= 0 {
+				return fmt.Errorf("invalid number of migrations to revert: %v", args[1])
+			}
+		}
+
+		count, err := migrator.Down(steps)
+		r.Logger.Infof("reverted %v migrations", count)
+
+		return err
+	default:
+		return fmt.Errorf("unknown direction %v, expected up or down", direction)
+	}
+}
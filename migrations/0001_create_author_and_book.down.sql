@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS Book;
+DROP TABLE IF EXISTS Author;
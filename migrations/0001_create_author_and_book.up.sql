@@ -0,0 +1,23 @@
+-- Baseline of the tables the service was first deployed with, the dates were stored as DD/MM/YYYY text
+CREATE TABLE IF NOT EXISTS Author (
+    authorId  INT         NOT NULL AUTO_INCREMENT,
+    firstName VARCHAR(50) NOT NULL,
+    lastName  VARCHAR(50) NOT NULL,
+    dob       VARCHAR(10) NOT NULL,
+    penName   VARCHAR(50) NOT NULL,
+    PRIMARY KEY (authorId)
+);
+
+CREATE TABLE IF NOT EXISTS Book (
+    bookId        INT          NOT NULL AUTO_INCREMENT,
+    title         VARCHAR(100) NOT NULL,
+    authorId      INT          NOT NULL,
+    Publication   VARCHAR(50)  NOT NULL,
+    PublishedDate VARCHAR(10)  NOT NULL,
+    PRIMARY KEY (bookId)
+);
+
+-- Before the ids were assigned by the database the client gave them, so tables created back then have ids
+-- without AUTO_INCREMENT. They get it here, ahead of 0005 as a column a foreign key refers to cannot change.
+ALTER TABLE Author MODIFY authorId INT NOT NULL AUTO_INCREMENT;
+ALTER TABLE Book MODIFY bookId INT NOT NULL AUTO_INCREMENT;
@@ -0,0 +1,3 @@
+-- The dates are kept as YYYY-MM-DD text, which the service reads as well
+ALTER TABLE Book MODIFY PublishedDate VARCHAR(10) NOT NULL;
+ALTER TABLE Author MODIFY dob VARCHAR(10) NOT NULL;
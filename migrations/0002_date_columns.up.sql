@@ -0,0 +1,7 @@
+-- The legacy DD/MM/YYYY dates are rewritten as YYYY-MM-DD before the columns become DATE
+UPDATE Author SET dob = DATE_FORMAT(STR_TO_DATE(dob, '%d/%m/%Y'), '%Y-%m-%d') WHERE dob LIKE '__/__/____';
+ALTER TABLE Author MODIFY dob DATE NOT NULL;
+
+UPDATE Book SET PublishedDate = DATE_FORMAT(STR_TO_DATE(PublishedDate, '%d/%m/%Y'), '%Y-%m-%d')
+    WHERE PublishedDate LIKE '__/__/____';
+ALTER TABLE Book MODIFY PublishedDate DATE NOT NULL;
@@ -0,0 +1,10 @@
+-- Every publication in use becomes a publisher, along with the ones the service used to accept
+CREATE TABLE Publisher (
+    publisherId INT         NOT NULL AUTO_INCREMENT,
+    name        VARCHAR(50) NOT NULL,
+    PRIMARY KEY (publisherId),
+    UNIQUE KEY uq_publisher_name (name)
+);
+
+INSERT IGNORE INTO Publisher (name) VALUES ('Scholastic'), ('Penguin'), ('Arihant');
+INSERT IGNORE INTO Publisher (name) SELECT DISTINCT Publication FROM Book;
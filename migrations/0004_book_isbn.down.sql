@@ -0,0 +1 @@
+ALTER TABLE Book DROP INDEX uq_book_isbn, DROP COLUMN isbn;
@@ -0,0 +1,2 @@
+-- A book without ISBN has NULL, which the unique index lets in any number of times
+ALTER TABLE Book ADD COLUMN isbn CHAR(13) NULL, ADD UNIQUE KEY uq_book_isbn (isbn);
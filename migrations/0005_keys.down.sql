@@ -0,0 +1,19 @@
+ALTER TABLE Author DROP INDEX uq_author_pen_name;
+
+ALTER TABLE Book
+    DROP FOREIGN KEY fk_book_author,
+    DROP FOREIGN KEY fk_book_publisher,
+    DROP INDEX uq_book_title,
+    DROP INDEX idx_book_published_date;
+
+-- The indexes MySQL created for the foreign keys outlive them
+ALTER TABLE Book DROP INDEX fk_book_author, DROP INDEX fk_book_publisher;
+
+-- The renamed authors and the removed books are put back as they were before the keys
+UPDATE Author JOIN migration_0005_renamed_authors renamed ON renamed.authorId = Author.authorId
+    SET Author.penName = renamed.penName;
+DROP TABLE migration_0005_renamed_authors;
+
+INSERT INTO Book (bookId, title, authorId, Publication, PublishedDate, isbn)
+    SELECT bookId, title, authorId, Publication, PublishedDate, isbn FROM migration_0005_removed_books;
+DROP TABLE migration_0005_removed_books;
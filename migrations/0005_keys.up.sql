@@ -0,0 +1,47 @@
+-- The stored rows may already break the keys below, so they are fixed first and reported in tables named
+-- after this migration: a book of a deleted author, and a copy of a book without ISBN but the one stored
+-- first, are moved to migration_0005_removed_books, and an author taking the pen name of an earlier one is
+-- renamed after its id, the former name is kept in migration_0005_renamed_authors.
+CREATE TABLE migration_0005_removed_books LIKE Book;
+ALTER TABLE migration_0005_removed_books ADD COLUMN reason VARCHAR(20) NOT NULL;
+
+INSERT INTO migration_0005_removed_books
+    SELECT Book.*, 'orphaned' FROM Book WHERE NOT EXISTS (SELECT 1 FROM Author WHERE Author.authorId = Book.authorId);
+DELETE Book FROM Book JOIN migration_0005_removed_books removed ON removed.bookId = Book.bookId;
+
+INSERT INTO migration_0005_removed_books
+    SELECT Book.*, 'duplicate' FROM Book
+    JOIN (SELECT title, authorId, Publication, MIN(bookId) AS kept FROM Book WHERE isbn IS NULL
+        GROUP BY title, authorId, Publication HAVING COUNT(*) > 1) copies
+    ON Book.isbn IS NULL AND Book.title = copies.title AND Book.authorId = copies.authorId
+        AND Book.Publication = copies.Publication AND Book.bookId <> copies.kept;
+
+DELETE Book FROM Book JOIN migration_0005_removed_books removed ON removed.bookId = Book.bookId;
+
+CREATE TABLE migration_0005_renamed_authors (
+    authorId INT         NOT NULL,
+    penName  VARCHAR(50) NOT NULL,
+    PRIMARY KEY (authorId)
+);
+
+INSERT INTO migration_0005_renamed_authors
+    SELECT Author.authorId, Author.penName FROM Author
+    JOIN (SELECT penName, MIN(authorId) AS kept FROM Author GROUP BY penName HAVING COUNT(*) > 1) taken
+    ON Author.penName = taken.penName AND Author.authorId <> taken.kept;
+
+UPDATE Author JOIN migration_0005_renamed_authors renamed ON renamed.authorId = Author.authorId
+    SET Author.penName = CONCAT(LEFT(renamed.penName, 38), ' #', Author.authorId);
+
+-- Every publication in use is a publisher before the books refer to the publishers
+INSERT IGNORE INTO Publisher (name) SELECT DISTINCT Publication FROM Book;
+
+-- A book belongs to a stored author and a registered publisher, renaming the publisher moves its books.
+-- A book without ISBN is unique by its title, author and publication, and a pen name is unique whatever
+-- its case.
+ALTER TABLE Book
+    ADD CONSTRAINT fk_book_author FOREIGN KEY (authorId) REFERENCES Author (authorId),
+    ADD CONSTRAINT fk_book_publisher FOREIGN KEY (Publication) REFERENCES Publisher (name) ON UPDATE CASCADE,
+    ADD UNIQUE KEY uq_book_title ((IF(isbn IS NULL, title, NULL)), authorId, Publication),
+    ADD KEY idx_book_published_date (PublishedDate);
+
+ALTER TABLE Author ADD UNIQUE KEY uq_author_pen_name (penName);
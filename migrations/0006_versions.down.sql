@@ -0,0 +1,2 @@
+ALTER TABLE Author DROP COLUMN version;
+ALTER TABLE Book DROP COLUMN version;
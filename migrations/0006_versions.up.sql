@@ -0,0 +1,3 @@
+-- The version of a row is bumped on every change, the stored rows start at the first version
+ALTER TABLE Book ADD COLUMN version INT NOT NULL DEFAULT 1;
+ALTER TABLE Author ADD COLUMN version INT NOT NULL DEFAULT 1;
@@ -0,0 +1,223 @@
+package migrations
+
+import (
+	"database/sql"
+	"embed"
+	"fmt"
+	"io/fs"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Files holds the migrations shipped with the service
+//
+//go:embed *.sql
+var Files embed.FS
+
+// fileName is the name of a migration file, <version>_<name>.up.sql or <version>_<name>.down.sql
+var fileName = regexp.MustCompile(`^(\d+)_(\w+)\.(up|down)\.sql$`)
+
+// Migration is a version of the schema along with the scripts moving to it and back from it
+type Migration struct {
+	Version int
+	Name    string
+	Up      string
+	Down    string
+}
+
+// Load method is to read the migrations of fsys in the order of their versions, every migration must have
+// both of its scripts
+func Load(fsys fs.FS) ([]Migration, error) {
+	entries, err := fs.ReadDir(fsys, ".")
+	if err != nil {
+		return nil, err
+	}
+
+	byVersion := make(map[int]*Migration)
+
+	for _, entry := range entries {
+		match := fileName.FindStringSubmatch(entry.Name())
+		if match == nil {
+			continue
+		}
+
+		script, err := fs.ReadFile(fsys, entry.Name())
+		if err != nil {
+			return nil, err
+		}
+
+		version, _ := strconv.Atoi(match[1])
+
+		m, ok := byVersion[version]
+		if !ok {
+			m = &Migration{Version: version, Name: match[2]}
+			byVersion[version] = m
+		}
+
+		if m.Name != match[2] {
+			return nil, fmt.Errorf("migration %v is named both %v and %v", version, m.Name, match[2])
+		}
+
+		if match[3] == "up" {
+			m.Up = string(script)
+		} else {
+			m.Down = string(script)
+		}
+	}
+
+	migrations := make([]Migration, 0, len(byVersion))
+
+	for _, m := range byVersion {
+		if m.Up == "" || m.Down == "" {
+			return nil, fmt.Errorf("migration %v_%v needs both an up and a down script", m.Version, m.Name)
+		}
+
+		migrations = append(migrations, *m)
+	}
+
+	sort.Slice(migrations, func(i, j int) bool { return migrations[i].Version < migrations[j].Version })
+
+	return migrations, nil
+}
+
+// Migrator is to move the schema of a database between the versions of the migrations, the applied
+// versions are recorded in the schema_migrations table
+type Migrator struct {
+	db         *sql.DB
+	migrations []Migration
+}
+
+func New(db *sql.DB, migrations []Migration) Migrator {
+	return Migrator{db: db, migrations: migrations}
+}
+
+// Up method is to apply the pending migrations in the order of their versions, the number of applied
+// migrations is returned
+func (m Migrator) Up() (int, error) {
+	applied, err := m.applied()
+	if err != nil {
+		return 0, err
+	}
+
+	count := 0
+
+	for _, migration := range m.migrations {
+		if applied[migration.Version] {
+			continue
+		}
+
+		if err := m.exec(migration.Up); err != nil {
+			return count, fmt.Errorf("migration %v_%v up: %w", migration.Version, migration.Name, err)
+		}
+
+		if _, err := m.db.Exec("INSERT INTO schema_migrations (version, name) VALUES (?, ?)", migration.Version,
+			migration.Name); err != nil {
+			return count, err
+		}
+
+		count++
+	}
+
+	return count, nil
+}
+
+// Down method is to revert the last steps applied migrations, the number of reverted migrations is returned
+func (m Migrator) Down(steps int) (int, error) {
+	applied, err := m.applied()
+	if err != nil {
+		return 0, err
+	}
+
+	count := 0
+
+	for i := len(m.migrations) - 1; i >= 0 && count < steps; i-- {
+		migration := m.migrations[i]
+		if !applied[migration.Version] {
+			continue
+		}
+
+		if err := m.exec(migration.Down); err != nil {
+			return count, fmt.Errorf("migration %v_%v down: %w", migration.Version, migration.Name, err)
+		}
+
+		if _, err := m.db.Exec("DELETE FROM schema_migrations WHERE version=?", migration.Version); err != nil {
+			return count, err
+		}
+
+		count++
+	}
+
+	return count, nil
+}
+
+// applied is to get the applied versions, the schema_migrations table is created when missing
+func (m Migrator) applied() (map[int]bool, error) {
+	if _, err := m.db.Exec("CREATE TABLE IF NOT EXISTS schema_migrations (version INT NOT NULL, " +
+		"name VARCHAR(100) NOT NULL, applied_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP, " +
+		"PRIMARY KEY (version))"); err != nil {
+		return nil, err
+	}
+
+	rows, err := m.db.Query("SELECT version FROM schema_migrations")
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	applied := make(map[int]bool)
+
+	for rows.Next() {
+		var version int
+
+		if err := rows.Scan(&version); err != nil {
+			return nil, err
+		}
+
+		applied[version] = true
+	}
+
+	return applied, rows.Err()
+}
+
+// exec is to run the statements of a script one by one, as the driver runs a single statement at a time
+func (m Migrator) exec(script string) error {
+	for _, statement := range statements(script) {
+		if _, err := m.db.Exec(statement); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// statements is to split a script into its statements, a statement ends with a semicolon at the end of a line
+// and the comment lines are dropped
+func statements(script string) []string {
+	var (
+		result  []string
+		current []string
+	)
+
+	for _, line := range strings.Split(script, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" || strings.HasPrefix(trimmed, "--") {
+			continue
+		}
+
+		current = append(current, line)
+
+		if strings.HasSuffix(trimmed, ";") {
+			result = append(result, strings.TrimSuffix(strings.TrimSpace(strings.Join(current, "\n")), ";"))
+			current = nil
+		}
+	}
+
+	if len(current) > 0 {
+		result = append(result, strings.TrimSpace(strings.Join(current, "\n")))
+	}
+
+	return result
+}
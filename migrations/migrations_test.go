@@ -0,0 +1,160 @@
+package migrations
+
+import (
+	"errors"
+	"log"
+	"reflect"
+	"testing"
+	"testing/fstest"
+
+	"github.com/DATA-DOG/go-sqlmock"
+)
+
+const createSchemaMigrations = "CREATE TABLE IF NOT EXISTS schema_migrations (version INT NOT NULL, " +
+	"name VARCHAR(100) NOT NULL, applied_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP, PRIMARY KEY (version))"
+
+// TestLoad function is to test reading the migrations in the order of their versions
+func TestLoad(t *testing.T) {
+	testcases := []struct {
+		desc       string
+		files      fstest.MapFS
+		migrations []Migration
+		err        bool
+	}{
+		{desc: "ordered by version", files: fstest.MapFS{
+			"0002_b.up.sql":   {Data: []byte("up b")},
+			"0002_b.down.sql": {Data: []byte("down b")},
+			"0001_a.up.sql":   {Data: []byte("up a")},
+			"0001_a.down.sql": {Data: []byte("down a")},
+			"README.md":       {Data: []byte("not a migration")},
+		}, migrations: []Migration{{Version: 1, Name: "a", Up: "up a", Down: "down a"},
+			{Version: 2, Name: "b", Up: "up b", Down: "down b"}}},
+		{desc: "missing down", files: fstest.MapFS{"0001_a.up.sql": {Data: []byte("up a")}}, err: true},
+		{desc: "two names", files: fstest.MapFS{"0001_a.up.sql": {Data: []byte("up a")},
+			"0001_b.down.sql": {Data: []byte("down b")}}, err: true},
+	}
+
+	for i, v := range testcases {
+		migrations, err := Load(v.files)
+
+		if !reflect.DeepEqual(migrations, v.migrations) && !v.err {
+			t.Errorf("desc : %v ,[TEST%d]Failed. Got %v\tExpected %v\n", v.desc, i+1, migrations, v.migrations)
+		}
+
+		if (err != nil) != v.err {
+			t.Errorf("desc : %v ,[TEST%d]Failed. Got %v\n", v.desc, i+1, err)
+		}
+	}
+}
+
+// TestLoad_Files function is to test that every shipped migration has both of its scripts
+func TestLoad_Files(t *testing.T) {
+	migrations, err := Load(Files)
+	if err != nil {
+		t.Fatalf("desc : shipped migrations ,Failed. Got %v\n", err)
+	}
+
+	for i, m := range migrations {
+		if m.Version != i+1 {
+			t.Errorf("desc : shipped migrations ,[TEST%d]Failed. Got version %v\tExpected %v\n", i+1, m.Version, i+1)
+		}
+	}
+}
+
+// TestStatements function is to test splitting a script into its statements
+func TestStatements(t *testing.T) {
+	script := "-- a comment\nCREATE TABLE A (\n    id INT\n);\n\nINSERT INTO A VALUES (1);\nDROP TABLE B"
+
+	expected := []string{"CREATE TABLE A (\n    id INT\n)", "INSERT INTO A VALUES (1)", "DROP TABLE B"}
+
+	if got := statements(script); !reflect.DeepEqual(got, expected) {
+		t.Errorf("desc : statements ,Failed. Got %q\tExpected %q\n", got, expected)
+	}
+}
+
+// TestMigrator_Up function is to test applying the pending migrations only
+func TestMigrator_Up(t *testing.T) {
+	migrations := []Migration{{Version: 1, Name: "a", Up: "CREATE TABLE A (id INT);", Down: "DROP TABLE A;"},
+		{Version: 2, Name: "b", Up: "CREATE TABLE B (id INT);\nCREATE TABLE C (id INT);", Down: "DROP TABLE B;"}}
+
+	testcases := []struct {
+		desc    string
+		applied []int
+		execErr error
+		count   int
+		err     bool
+	}{
+		{desc: "pending migration", applied: []int{1}, count: 1},
+		{desc: "up to date", applied: []int{1, 2}},
+		{desc: "error in script", applied: []int{1}, execErr: errors.New("error in script"), err: true},
+	}
+
+	for i, v := range testcases {
+		db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+		if err != nil {
+			log.Printf("an error '%s' was not expected when opening a stub database connection", err)
+		}
+
+		rows := sqlmock.NewRows([]string{"version"})
+		for _, version := range v.applied {
+			rows.AddRow(version)
+		}
+
+		mock.ExpectExec(createSchemaMigrations).WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectQuery("SELECT version FROM schema_migrations").WillReturnRows(rows)
+
+		if len(v.applied) < len(migrations) {
+			mock.ExpectExec("CREATE TABLE B (id INT)").WillReturnResult(sqlmock.NewResult(0, 0))
+			mock.ExpectExec("CREATE TABLE C (id INT)").WillReturnResult(sqlmock.NewResult(0, 0)).
+				WillReturnError(v.execErr)
+
+			if v.execErr == nil {
+				mock.ExpectExec("INSERT INTO schema_migrations (version, name) VALUES (?, ?)").WithArgs(2, "b").
+					WillReturnResult(sqlmock.NewResult(0, 1))
+			}
+		}
+
+		count, err := New(db, migrations).Up()
+
+		if count != v.count || (err != nil) != v.err {
+			t.Errorf("desc : %v ,[TEST%d]Failed. Got %v, %v\tExpected %v\n", v.desc, i+1, count, err, v.count)
+		}
+
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Errorf("desc : %v ,[TEST%d]Failed. Got %v\n", v.desc, i+1, err)
+		}
+
+		db.Close()
+	}
+}
+
+// TestMigrator_Down function is to test reverting the last applied migrations
+func TestMigrator_Down(t *testing.T) {
+	migrations := []Migration{{Version: 1, Name: "a", Up: "CREATE TABLE A (id INT);", Down: "DROP TABLE A;"},
+		{Version: 2, Name: "b", Up: "CREATE TABLE B (id INT);", Down: "DROP TABLE B;"},
+		{Version: 3, Name: "c", Up: "CREATE TABLE C (id INT);", Down: "DROP TABLE C;"}}
+
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	if err != nil {
+		log.Printf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+
+	defer db.Close()
+
+	mock.ExpectExec(createSchemaMigrations).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery("SELECT version FROM schema_migrations").
+		WillReturnRows(sqlmock.NewRows([]string{"version"}).AddRow(1).AddRow(2))
+	mock.ExpectExec("DROP TABLE B").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec("DELETE FROM schema_migrations WHERE version=?").WithArgs(2).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	count, err := New(db, migrations).Down(1)
+
+	if count != 1 || err != nil {
+		t.Errorf("desc : last applied reverted ,Failed. Got %v, %v\tExpected 1\n", count, err)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("desc : last applied reverted ,Failed. Got %v\n", err)
+	}
+}
@@ -1,9 +1,31 @@
 package models
 
+import "encoding/json"
+
 type Author struct {
 	AuthID    int    `json:"authID,omitempty"`
-	FirstName string `json:"firstName,omitempty"`
-	LastName  string `json:"lastName,omitempty"`
-	Dob       string `json:"dob,omitempty"`
-	PenName   string `json:"penName,omitempty"`
+	FirstName string `json:"firstName,omitempty" validate:"required,max=50"`
+	LastName  string `json:"lastName,omitempty" validate:"required,max=50"`
+	Dob       Date   `json:"dob,omitempty" validate:"required,date,born"`
+	PenName   string `json:"penName,omitempty" validate:"required,max=50"`
+	// Version is bumped on every change of the stored author, it travels in the ETag header and not in the body
+	Version int `json:"-"`
+}
+
+// MarshalJSON method is to write the author with the fields of Author, leaving out a date of birth that is not
+// set as omitempty does not leave out a struct, so that a book without its author keeps "auth":{}
+func (a Author) MarshalJSON() ([]byte, error) {
+	var dob *Date
+
+	if !a.Dob.IsZero() {
+		dob = &a.Dob
+	}
+
+	return json.Marshal(struct {
+		AuthID    int    `json:"authID,omitempty"`
+		FirstName string `json:"firstName,omitempty"`
+		LastName  string `json:"lastName,omitempty"`
+		Dob       *Date  `json:"dob,omitempty"`
+		PenName   string `json:"penName,omitempty"`
+	}{a.AuthID, a.FirstName, a.LastName, dob, a.PenName})
 }
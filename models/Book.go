@@ -2,9 +2,12 @@ package models
 
 type Book struct {
 	BookID        int    `json:"bookID"`
-	AuthorID      int    `json:"authID"`
+	AuthorID      int    `json:"authID" validate:"required"`
 	Auth          Author `json:"auth,omitempty"`
-	Title         string `json:"title"`
-	Publication   string `json:"publication"`
-	PublishedDate string `json:"publishedDate"`
+	Title         string `json:"title" validate:"required,max=100"`
+	Publication   string `json:"publication" validate:"required,publication"`
+	PublishedDate Date   `json:"publishedDate" validate:"required,date,published"`
+	ISBN          ISBN   `json:"isbn,omitempty" validate:"isbn"`
+	// Version is bumped on every change of the stored book, it travels in the ETag header and not in the body
+	Version int `json:"-"`
 }
@@ -0,0 +1,18 @@
+package models
+
+// Title matching modes of BookFilter
+const (
+	MatchExact    = "exact"
+	MatchPrefix   = "prefix"
+	MatchContains = "contains"
+)
+
+// BookFilter holds the criteria of a book listing, all the set fields are combined with AND
+type BookFilter struct {
+	Title           string
+	TitleMatch      string
+	AuthorID        int
+	Publication     string
+	PublishedAfter  Date
+	PublishedBefore Date
+}
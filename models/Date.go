@@ -0,0 +1,140 @@
+package models
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+const (
+	// DateLayout is the ISO-8601 layout every date is written in
+	DateLayout = "2006-01-02"
+	// legacyDateLayout is the dd/mm/yyyy layout still accepted for backward compatibility
+	legacyDateLayout = "02/01/2006"
+)
+
+// Date is a calendar day. It is read from yyyy-mm-dd or dd/mm/yyyy, and always written as yyyy-mm-dd,
+// in JSON as well as in the DATE columns.
+type Date struct {
+	t time.Time
+	// raw is the input which is not a date, kept for the validation to report it
+	raw string
+}
+
+// NewDate is to get the Date of the given day
+func NewDate(year int, month time.Month, day int) Date {
+	return Date{t: time.Date(year, month, day, 0, 0, 0, 0, time.UTC)}
+}
+
+// ParseDate is to read a date in yyyy-mm-dd or dd/mm/yyyy. Days which are not on the calendar, like 31/02,
+// are rejected, in which case the returned Date holds the input and is not valid.
+func ParseDate(s string) (Date, error) {
+	for _, layout := range []string{DateLayout, legacyDateLayout} {
+		if t, err := time.Parse(layout, s); err == nil {
+			return Date{t: t}, nil
+		}
+	}
+
+	return Date{raw: s}, fmt.Errorf("invalid date %q", s)
+}
+
+// Time is to get the midnight UTC of the day
+func (d Date) Time() time.Time {
+	return d.t
+}
+
+// IsZero is to check that no date is set
+func (d Date) IsZero() bool {
+	return d.t.IsZero() && d.raw == ""
+}
+
+// IsValid is to check that the date was read from a valid input
+func (d Date) IsValid() bool {
+	return d.raw == ""
+}
+
+// String is to get the date as yyyy-mm-dd, or the input it was read from when that is not a date
+func (d Date) String() string {
+	switch {
+	case !d.IsValid():
+		return d.raw
+	case d.IsZero():
+		return ""
+	}
+
+	return d.t.Format(DateLayout)
+}
+
+// MarshalJSON method is to write the date as "yyyy-mm-dd", or null when it is not set
+func (d Date) MarshalJSON() ([]byte, error) {
+	if d.IsZero() {
+		return []byte("null"), nil
+	}
+
+	return json.Marshal(d.String())
+}
+
+// UnmarshalJSON method is to read the date from a JSON string. An input which is not a date is kept
+// instead of failing the whole payload, so that it is reported along with the other invalid fields.
+func (d *Date) UnmarshalJSON(b []byte) error {
+	var s *string
+
+	if err := json.Unmarshal(b, &s); err != nil {
+		return err
+	}
+
+	if s == nil || *s == "" {
+		*d = Date{}
+
+		return nil
+	}
+
+	*d, _ = ParseDate(*s)
+
+	return nil
+}
+
+// Scan method is to read the date from a DATE column
+func (d *Date) Scan(src interface{}) error {
+	switch v := src.(type) {
+	case nil:
+		*d = Date{}
+
+		return nil
+	case time.Time:
+		*d = NewDate(v.Date())
+
+		return nil
+	case []byte:
+		return d.scanString(string(v))
+	case string:
+		return d.scanString(v)
+	}
+
+	return fmt.Errorf("cannot scan %T into a date", src)
+}
+
+// scanString is to read the date from the text of a DATE column
+func (d *Date) scanString(s string) error {
+	t, err := time.Parse(DateLayout, s)
+	if err != nil {
+		return err
+	}
+
+	*d = Date{t: t}
+
+	return nil
+}
+
+// Value method is to write the date to a DATE column
+func (d Date) Value() (driver.Value, error) {
+	switch {
+	case !d.IsValid():
+		return nil, fmt.Errorf("invalid date %q", d.raw)
+	case d.IsZero():
+		return nil, nil
+	}
+
+	return d.String(), nil
+}
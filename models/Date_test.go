@@ -0,0 +1,97 @@
+package models
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// TestParseDate function is to test reading ISO-8601 and legacy dates
+func TestParseDate(t *testing.T) {
+	testcases := []struct {
+		desc  string
+		input string
+		date  Date
+		valid bool
+	}{
+		{desc: "iso", input: "2016-03-16", date: NewDate(2016, 3, 16), valid: true},
+		{desc: "legacy", input: "16/03/2016", date: NewDate(2016, 3, 16), valid: true},
+		{desc: "leap day", input: "29/02/2016", date: NewDate(2016, 2, 29), valid: true},
+		{desc: "not on the calendar", input: "31/02/2016", date: Date{raw: "31/02/2016"}},
+		{desc: "month out of range", input: "2016-13-01", date: Date{raw: "2016-13-01"}},
+		{desc: "single digit day", input: "2016-3-6", date: Date{raw: "2016-3-6"}},
+		{desc: "empty", input: "", date: Date{}},
+	}
+
+	for i, v := range testcases {
+		date, err := ParseDate(v.input)
+
+		if !reflect.DeepEqual(date, v.date) || (err == nil) != v.valid {
+			t.Errorf("desc : %v ,[TEST%d]Failed. Got %v, %v\tExpected %v\n", v.desc, i+1, date, err, v.date)
+		}
+	}
+}
+
+// TestDate_JSON function is to test that dates are always written as yyyy-mm-dd
+func TestDate_JSON(t *testing.T) {
+	testcases := []struct {
+		desc   string
+		input  string
+		date   Date
+		output string
+	}{
+		{desc: "iso", input: `"2016-03-16"`, date: NewDate(2016, 3, 16), output: `"2016-03-16"`},
+		{desc: "legacy", input: `"16/03/2016"`, date: NewDate(2016, 3, 16), output: `"2016-03-16"`},
+		{desc: "invalid is kept", input: `"31/02/2016"`, date: Date{raw: "31/02/2016"}, output: `"31/02/2016"`},
+		{desc: "empty", input: `""`, date: Date{}, output: "null"},
+		{desc: "null", input: "null", date: Date{}, output: "null"},
+	}
+
+	for i, v := range testcases {
+		var date Date
+
+		if err := json.Unmarshal([]byte(v.input), &date); err != nil || !reflect.DeepEqual(date, v.date) {
+			t.Errorf("desc : %v ,[TEST%d]Failed. Got %v, %v\tExpected %v\n", v.desc, i+1, date, err, v.date)
+		}
+
+		output, _ := json.Marshal(date)
+
+		if string(output) != v.output {
+			t.Errorf("desc : %v ,[TEST%d]Failed. Got %s\tExpected %v\n", v.desc, i+1, output, v.output)
+		}
+	}
+
+	var date Date
+
+	if err := json.Unmarshal([]byte("20160316"), &date); err == nil {
+		t.Errorf("desc : number ,Failed. Got %v\tExpected an error\n", date)
+	}
+}
+
+// TestDate_Scan function is to test reading a DATE column
+func TestDate_Scan(t *testing.T) {
+	testcases := []struct {
+		desc  string
+		src   interface{}
+		date  Date
+		isErr bool
+	}{
+		{desc: "text", src: []byte("2016-03-16"), date: NewDate(2016, 3, 16)},
+		{desc: "string", src: "2016-03-16", date: NewDate(2016, 3, 16)},
+		{desc: "parsed time", src: time.Date(2016, 3, 16, 0, 0, 0, 0, time.Local), date: NewDate(2016, 3, 16)},
+		{desc: "null", src: nil, date: Date{}},
+		{desc: "legacy text", src: "16/03/2016", isErr: true},
+		{desc: "number", src: 20160316, isErr: true},
+	}
+
+	for i, v := range testcases {
+		var date Date
+
+		err := date.Scan(v.src)
+
+		if !reflect.DeepEqual(date, v.date) || (err != nil) != v.isErr {
+			t.Errorf("desc : %v ,[TEST%d]Failed. Got %v, %v\tExpected %v\n", v.desc, i+1, date, err, v.date)
+		}
+	}
+}
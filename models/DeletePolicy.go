@@ -0,0 +1,15 @@
+package models
+
+// Policies for the books of an author being deleted
+const (
+	DeleteReject   = "reject"
+	DeleteCascade  = "cascade"
+	DeleteReassign = "reassign"
+)
+
+// DeletePolicy holds how the books of an author are handled when the author is deleted,
+// ReassignTo is the author the books are moved to with DeleteReassign
+type DeletePolicy struct {
+	Books      string
+	ReassignTo int
+}
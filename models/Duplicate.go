@@ -0,0 +1,11 @@
+package models
+
+// Rules a book is told to be a duplicate of another stored one by, a book with an ISBN is a duplicate of
+// the stored one with the same ISBN whatever the rule
+const (
+	// DuplicateISBN is a book without ISBN with the same title, author and publication as a stored one without
+	// ISBN, which is what the uq_book_title index holds
+	DuplicateISBN = "isbn"
+	// DuplicateTitle is a book with the same title, author and publication as any stored one
+	DuplicateTitle = "title"
+)
@@ -0,0 +1,100 @@
+package models
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"strings"
+)
+
+// ISBN is the ISBN-13 of a book, "" when the book has none. It is stored as NULL when empty so that the
+// unique index lets any number of books without ISBN in.
+type ISBN string
+
+// ParseISBN is to read an ISBN-10 or ISBN-13 with or without hyphens and spaces, the checksum must hold.
+// An ISBN-10 is converted to its ISBN-13.
+func ParseISBN(s string) (ISBN, error) {
+	digits := strings.ToUpper(strings.NewReplacer("-", "", " ", "").Replace(s))
+
+	switch {
+	case len(digits) == 10 && isISBN10(digits):
+		return ISBN(isbn13("978" + digits[:9])), nil
+	case len(digits) == 13 && isISBN13(digits):
+		return ISBN(digits), nil
+	default:
+		return "", fmt.Errorf("invalid isbn %q", s)
+	}
+}
+
+// isISBN10 is to check the digits and the mod 11 checksum of an ISBN-10, its check digit can be X
+func isISBN10(digits string) bool {
+	sum := 0
+
+	for i := 0; i < 10; i++ {
+		var d int
+
+		switch c := digits[i]; {
+		case c >= '0' && c <= '9':
+			d = int(c - '0')
+		case c == 'X' && i == 9:
+			d = 10
+		default:
+			return false
+		}
+
+		sum += (10 - i) * d
+	}
+
+	return sum%11 == 0
+}
+
+// isISBN13 is to check the digits and the mod 10 checksum of an ISBN-13
+func isISBN13(digits string) bool {
+	for i := 0; i < 13; i++ {
+		if digits[i] < '0' || digits[i] > '9' {
+			return false
+		}
+	}
+
+	return isbn13(digits[:12]) == digits
+}
+
+// isbn13 is to append the check digit to the first 12 digits of an ISBN-13
+func isbn13(digits string) string {
+	sum := 0
+
+	for i := 0; i < 12; i++ {
+		d := int(digits[i] - '0')
+		if i%2 == 1 {
+			d *= 3
+		}
+
+		sum += d
+	}
+
+	return digits + string(rune('0'+(10-sum%10)%10))
+}
+
+// Scan is to read the ISBN from a nullable column
+func (i *ISBN) Scan(value interface{}) error {
+	switch v := value.(type) {
+	case nil:
+		*i = ""
+	case []byte:
+		*i = ISBN(v)
+	case string:
+		*i = ISBN(v)
+	default:
+		return fmt.Errorf("cannot scan %T into an isbn", value)
+	}
+
+	return nil
+}
+
+// Value is to write the ISBN, an empty one is written as NULL
+func (i ISBN) Value() (driver.Value, error) {
+	if i == "" {
+		return nil, nil
+	}
+
+	return string(i), nil
+}
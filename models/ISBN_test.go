@@ -0,0 +1,58 @@
+package models
+
+import (
+	"testing"
+)
+
+// TestParseISBN function is to test reading ISBN-10 and ISBN-13 as a normalized ISBN-13
+func TestParseISBN(t *testing.T) {
+	testcases := []struct {
+		desc  string
+		input string
+		isbn  ISBN
+		valid bool
+	}{
+		{desc: "isbn-13", input: "9780306406157", isbn: "9780306406157", valid: true},
+		{desc: "isbn-13 with hyphens", input: "978-0-306-40615-7", isbn: "9780306406157", valid: true},
+		{desc: "isbn-10 converted", input: "0-306-40615-2", isbn: "9780306406157", valid: true},
+		{desc: "isbn-10 with X check digit", input: "0 8044 2957 x", isbn: "9780804429573", valid: true},
+		{desc: "isbn-13 bad checksum", input: "978-0-306-40615-8"},
+		{desc: "isbn-10 bad checksum", input: "0-306-40615-3"},
+		{desc: "X not as check digit", input: "0-306-4X615-2"},
+		{desc: "bad length", input: "978030640615"},
+		{desc: "empty", input: ""},
+	}
+
+	for i, v := range testcases {
+		isbn, err := ParseISBN(v.input)
+
+		if isbn != v.isbn || (err == nil) != v.valid {
+			t.Errorf("desc : %v ,[TEST%d]Failed. Got %v, %v\tExpected %v\n", v.desc, i+1, isbn, err, v.isbn)
+		}
+	}
+}
+
+// TestISBN_Scan function is to test that a NULL isbn is read as an empty one and written back as NULL
+func TestISBN_Scan(t *testing.T) {
+	testcases := []struct {
+		desc  string
+		value interface{}
+		isbn  ISBN
+	}{
+		{desc: "null", value: nil, isbn: ""},
+		{desc: "bytes", value: []byte("9780306406157"), isbn: "9780306406157"},
+		{desc: "string", value: "9780306406157", isbn: "9780306406157"},
+	}
+
+	for i, v := range testcases {
+		var isbn ISBN
+
+		if err := isbn.Scan(v.value); err != nil || isbn != v.isbn {
+			t.Errorf("desc : %v ,[TEST%d]Failed. Got %v, %v\tExpected %v\n", v.desc, i+1, isbn, err, v.isbn)
+		}
+
+		if value, _ := isbn.Value(); (value == nil) != (v.value == nil) {
+			t.Errorf("desc : %v ,[TEST%d]Failed. Got %v\tExpected %v\n", v.desc, i+1, value, v.value)
+		}
+	}
+}
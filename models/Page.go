@@ -0,0 +1,16 @@
+package models
+
+// Page holds the pagination parameters of a listing request
+type Page struct {
+	Limit  int
+	Offset int
+	Cursor string
+	// After is the decoded Cursor, the sort key values of the last row of the previous page
+	After []string
+}
+
+// PageMeta is the pagination metadata returned along with a listing
+type PageMeta struct {
+	NextCursor string `json:"nextCursor,omitempty"`
+	Total      int    `json:"total"`
+}
@@ -0,0 +1,20 @@
+package models
+
+// BookPatch holds the fields of a JSON merge patch of a Book, nil fields are left unchanged
+// and the given ones follow the rules of Book
+type BookPatch struct {
+	AuthorID      *int    `json:"authID" validate:"required"`
+	Title         *string `json:"title" validate:"required,max=100"`
+	Publication   *string `json:"publication" validate:"required,publication"`
+	PublishedDate *Date   `json:"publishedDate" validate:"required,date,published"`
+	ISBN          *ISBN   `json:"isbn" validate:"isbn"`
+}
+
+// AuthorPatch holds the fields of a JSON merge patch of an Author, nil fields are left unchanged
+// and the given ones follow the rules of Author
+type AuthorPatch struct {
+	FirstName *string `json:"firstName" validate:"required,max=50"`
+	LastName  *string `json:"lastName" validate:"required,max=50"`
+	Dob       *Date   `json:"dob" validate:"required,date,born"`
+	PenName   *string `json:"penName" validate:"required,max=50"`
+}
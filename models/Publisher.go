@@ -0,0 +1,6 @@
+package models
+
+type Publisher struct {
+	PublisherID int    `json:"publisherID"`
+	Name        string `json:"name" validate:"required,max=50"`
+}
@@ -0,0 +1,7 @@
+package models
+
+// SortField is a field a listing is ordered by
+type SortField struct {
+	Field string
+	Desc  bool
+}
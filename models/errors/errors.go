@@ -0,0 +1,98 @@
+package errors
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Machine readable codes of the domain errors, these are part of the API and must not change
+const (
+	CodeInvalidParam      = "INVALID_PARAM"
+	CodeMissingParam      = "MISSING_PARAM"
+	CodeInvalidField      = "INVALID_FIELD"
+	CodeNotFound          = "NOT_FOUND"
+	CodeAuthorHasBooks    = "AUTHOR_HAS_BOOKS"
+	CodePublisherExists   = "PUBLISHER_EXISTS"
+	CodePublisherHasBooks = "PUBLISHER_HAS_BOOKS"
+	CodePenNameTaken      = "PEN_NAME_TAKEN"
+	CodeBookExists        = "BOOK_EXISTS"
+	CodeVersionMismatch   = "VERSION_MISMATCH"
+)
+
+// InvalidParam is returned when a path or query param is missing or malformed
+type InvalidParam struct {
+	Code   string
+	Params []string
+}
+
+func (e InvalidParam) Error() string {
+	if e.Code == CodeMissingParam {
+		return fmt.Sprintf("missing param: %v", strings.Join(e.Params, ", "))
+	}
+
+	return fmt.Sprintf("invalid param: %v", strings.Join(e.Params, ", "))
+}
+
+// Reasons a field of a payload is rejected for
+const (
+	ReasonMissing            = "missing"
+	ReasonTooLong            = "too long"
+	ReasonBadFormat          = "bad format"
+	ReasonOutOfRange         = "out of range"
+	ReasonUnknownPublication = "unknown publication"
+	ReasonNotRemovable       = "cannot be removed"
+)
+
+// FieldError is an offending field of a payload along with the reason it is rejected
+type FieldError struct {
+	Field  string `json:"field"`
+	Reason string `json:"reason"`
+}
+
+// Validation is returned when a payload is well formed but breaks the rules of the entity,
+// every offending field is reported
+type Validation struct {
+	Code   string
+	Fields []FieldError
+	Reason string
+}
+
+func (e Validation) Error() string {
+	fields := make([]string, len(e.Fields))
+	for i := range e.Fields {
+		fields[i] = e.Fields[i].Field + " " + e.Fields[i].Reason
+	}
+
+	return fmt.Sprintf("%v: %v", e.Reason, strings.Join(fields, ", "))
+}
+
+// NotFound is returned when the entity with the given ID does not exist
+type NotFound struct {
+	Entity string
+	ID     string
+}
+
+func (e NotFound) Error() string {
+	return fmt.Sprintf("%v %v not found", e.Entity, e.ID)
+}
+
+// Conflict is returned when the request clashes with the stored state
+type Conflict struct {
+	Code       string
+	Reason     string
+	ResourceID string
+}
+
+func (e Conflict) Error() string {
+	return e.Reason
+}
+
+// PreconditionFailed is returned when the entity has changed since the version the request is conditioned on
+type PreconditionFailed struct {
+	Entity string
+	ID     string
+}
+
+func (e PreconditionFailed) Error() string {
+	return fmt.Sprintf("%v %v has changed since the version in If-Match", e.Entity, e.ID)
+}
@@ -0,0 +1,131 @@
+package validate
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"mytest/models"
+	"mytest/models/errors"
+)
+
+// Check is a named rule of the validate tag, it returns the reason the value breaks the rule
+// or "" when the rule holds
+type Check func(value string) string
+
+// Struct method is to check the fields of a struct against the comma separated rules of their
+// validate tags, e.g. `validate:"required,max=50,date"`. Every offending field is reported once, with the
+// reason of the first rule it breaks, under its json name. Nil pointer fields are not checked, which is
+// how a patch validates only the given fields. An empty isbn is let in as the ISBN is optional. Rules other
+// than required, max, date and isbn are looked up in checks.
+func Struct(v interface{}, checks map[string]Check) []errors.FieldError {
+	val := reflect.Indirect(reflect.ValueOf(v))
+	typ := val.Type()
+
+	var fields []errors.FieldError
+
+	for i := 0; i < typ.NumField(); i++ {
+		tag := typ.Field(i).Tag.Get("validate")
+		if tag == "" {
+			continue
+		}
+
+		field := val.Field(i)
+		if field.Kind() == reflect.Ptr {
+			if field.IsNil() {
+				continue
+			}
+
+			field = field.Elem()
+		}
+
+		if reason := checkField(field, strings.Split(tag, ","), checks); reason != "" {
+			fields = append(fields, errors.FieldError{Field: jsonName(typ.Field(i)), Reason: reason})
+		}
+	}
+
+	return fields
+}
+
+// checkField is to check a field against its rules, returning the reason of the first broken one
+func checkField(field reflect.Value, rules []string, checks map[string]Check) string {
+	value := fieldString(field)
+
+	for _, rule := range rules {
+		name, arg := rule, ""
+		if i := strings.Index(rule, "="); i >= 0 {
+			name, arg = rule[:i], rule[i+1:]
+		}
+
+		var reason string
+
+		switch name {
+		case "required":
+			if field.IsZero() {
+				reason = errors.ReasonMissing
+			}
+		case "max":
+			if max, _ := strconv.Atoi(arg); utf8.RuneCountInString(value) > max {
+				reason = errors.ReasonTooLong
+			}
+		case "date":
+			if !isDate(field, value) {
+				reason = errors.ReasonBadFormat
+			}
+		case "isbn":
+			if _, err := models.ParseISBN(value); value != "" && err != nil {
+				reason = errors.ReasonBadFormat
+			}
+		default:
+			if check, ok := checks[name]; ok {
+				reason = check(value)
+			}
+		}
+
+		if reason != "" {
+			return reason
+		}
+	}
+
+	return ""
+}
+
+// isDate is to check that a models.Date was read from a valid date, or that a string is one
+func isDate(field reflect.Value, value string) bool {
+	if date, ok := field.Interface().(models.Date); ok {
+		return date.IsValid()
+	}
+
+	_, err := models.ParseDate(value)
+
+	return err == nil
+}
+
+// fieldString is to get the value a rule is checked against
+func fieldString(field reflect.Value) string {
+	if s, ok := field.Interface().(fmt.Stringer); ok {
+		return s.String()
+	}
+
+	if field.Kind() == reflect.String {
+		return field.String()
+	}
+
+	if field.CanInt() {
+		return strconv.FormatInt(field.Int(), 10)
+	}
+
+	return ""
+}
+
+// jsonName is to get the name of the field in the payload
+func jsonName(f reflect.StructField) string {
+	name := strings.Split(f.Tag.Get("json"), ",")[0]
+	if name == "" || name == "-" {
+		return f.Name
+	}
+
+	return name
+}
@@ -0,0 +1,66 @@
+package validate
+
+import (
+	"reflect"
+	"testing"
+
+	"mytest/models"
+	"mytest/models/errors"
+)
+
+type payload struct {
+	ID     int         `json:"id" validate:"required"`
+	Name   string      `json:"name,omitempty" validate:"required,max=5"`
+	Date   *string     `json:"date" validate:"required,date,recent"`
+	Born   models.Date `json:"born" validate:"date"`
+	ISBN   models.ISBN `json:"isbn" validate:"isbn"`
+	Note   string
+	Hidden string `json:"-" validate:"required"`
+}
+
+// TestStruct function is to test checking a struct against its validate tags
+func TestStruct(t *testing.T) {
+	date, isoDate, badDate, oldDate, empty := "16/03/2016", "2016-03-16", "30/02/2016", "16/03/1916", ""
+	notBorn, _ := models.ParseDate("2001-13-01")
+
+	checks := map[string]Check{
+		"recent": func(value string) string {
+			if date, _ := models.ParseDate(value); date.Time().Year() < 2000 {
+				return errors.ReasonOutOfRange
+			}
+
+			return ""
+		},
+	}
+
+	testcases := []struct {
+		desc   string
+		input  payload
+		fields []errors.FieldError
+	}{
+		{desc: "valid", input: payload{ID: 1, Name: "Ravi", Date: &date, Hidden: "x"}},
+		{desc: "iso date", input: payload{ID: 1, Name: "Ravi", Date: &isoDate, Born: models.NewDate(2001, 4, 6),
+			Hidden: "x"}},
+		{desc: "nil pointer is not checked", input: payload{ID: 1, Name: "Ravi", Hidden: "x"}},
+		{desc: "every field reported", input: payload{Name: "Ravindra", Date: &badDate}, fields: []errors.FieldError{
+			{Field: "id", Reason: errors.ReasonMissing}, {Field: "name", Reason: errors.ReasonTooLong},
+			{Field: "date", Reason: errors.ReasonBadFormat}, {Field: "Hidden", Reason: errors.ReasonMissing}}},
+		{desc: "empty pointer", input: payload{ID: 1, Name: "Ravi", Date: &empty, Hidden: "x"},
+			fields: []errors.FieldError{{Field: "date", Reason: errors.ReasonMissing}}},
+		{desc: "invalid date type", input: payload{ID: 1, Name: "Ravi", Born: notBorn, Hidden: "x"},
+			fields: []errors.FieldError{{Field: "born", Reason: errors.ReasonBadFormat}}},
+		{desc: "isbn", input: payload{ID: 1, Name: "Ravi", ISBN: "0-306-40615-2", Hidden: "x"}},
+		{desc: "invalid isbn", input: payload{ID: 1, Name: "Ravi", ISBN: "0-306-40615-3", Hidden: "x"},
+			fields: []errors.FieldError{{Field: "isbn", Reason: errors.ReasonBadFormat}}},
+		{desc: "custom check", input: payload{ID: 1, Name: "Ravi", Date: &oldDate, Hidden: "x"},
+			fields: []errors.FieldError{{Field: "date", Reason: errors.ReasonOutOfRange}}},
+	}
+
+	for i, v := range testcases {
+		fields := Struct(&v.input, checks)
+
+		if !reflect.DeepEqual(fields, v.fields) {
+			t.Errorf("desc : %v ,[TEST%d]Failed. Got %v\tExpected %v\n", v.desc, i+1, fields, v.fields)
+		}
+	}
+}
@@ -1,30 +1,46 @@
 package author
 
 import (
-	"developer.zopsmart.com/go/gofr/pkg/errors"
+	"database/sql"
 	"developer.zopsmart.com/go/gofr/pkg/gofr"
+	"fmt"
 	"mytest/datastore"
 	"mytest/models"
+	"mytest/models/errors"
+	"mytest/models/validate"
 	"strconv"
+	"time"
 )
 
+// The ages in years an author can be of, on the day of the clock
+const (
+	minAge = 10
+	maxAge = 130
+)
+
+var errInvalidID = errors.InvalidParam{Code: errors.CodeInvalidParam, Params: []string{"id"}}
+
 type Service struct {
 	datastore datastore.Author
+	// unitOfWork is to delete an author in the same transaction its books are handled in
+	unitOfWork datastore.UnitOfWork
+	// now is the clock the date of birth is checked against
+	now func() time.Time
 }
 
-func New(author datastore.Author) Service {
-	return Service{author}
+// New is to build the author Service, an author must be between minAge and maxAge on the day of the clock
+func New(author datastore.Author, unitOfWork datastore.UnitOfWork, now func() time.Time) Service {
+	return Service{datastore: author, unitOfWork: unitOfWork, now: now}
 }
 
-// Post Author details
+// Post Author details, the AuthID is assigned by the datastore and the pen name must not be taken
 func (s Service) Post(c *gofr.Context, auth models.Author) (models.Author, error) {
-	// Checking for invalid id
-	if auth.AuthID <= 0 {
-		return models.Author{}, errors.Error("invalid id")
+	if err := s.validateAuthor(auth); err != nil {
+		return models.Author{}, err
 	}
 
-	if isMissingFields(auth) {
-		return models.Author{}, errors.Error("missing fields")
+	if err := s.checkPenName(c, 0, auth.PenName); err != nil {
+		return models.Author{}, err
 	}
 
 	author, err := s.datastore.Post(c, auth)
@@ -35,46 +51,121 @@ func (s Service) Post(c *gofr.Context, auth models.Author) (models.Author, error
 	return author, nil
 }
 
-// Update Author details
-func (s Service) Update(c *gofr.Context, id int, auth models.Author) (models.Author, error) {
+// GetAll Author details
+func (s Service) GetAll(c *gofr.Context) ([]models.Author, error) {
+	authors, err := s.datastore.GetAll(c)
+	if err != nil {
+		return []models.Author{}, err
+	}
+
+	return authors, nil
+}
+
+// GetByID Author details by its ID
+func (s Service) GetByID(c *gofr.Context, id int) (models.Author, error) {
 	// Checking invalid id
 	if id <= 0 {
-		return models.Author{}, errors.Error("invalid id")
+		return models.Author{}, errInvalidID
 	}
 
-	if isMissingFields(auth) {
-		return models.Author{}, errors.Error("missing fields")
+	if err := s.checkAuthor(c, id); err != nil {
+		return models.Author{}, err
 	}
 
-	// Checking author ID present or not
-	check := s.datastore.IsAuthorIDPresent(c, id)
-	if check {
-		return models.Author{}, errors.EntityNotFound{Entity: "Author", ID: strconv.Itoa(id)}
+	author, err := s.datastore.GetByID(c, id)
+	if err != nil {
+		return models.Author{}, err
 	}
 
-	author, err := s.datastore.Update(c, id, auth)
+	return author, nil
+}
+
+// Update Author details, the stored author is returned
+func (s Service) Update(c *gofr.Context, id int, auth models.Author, version int) (models.Author, error) {
+	// Checking invalid id
+	if id <= 0 {
+		return models.Author{}, errInvalidID
+	}
+
+	if err := s.validateAuthor(auth); err != nil {
+		return models.Author{}, err
+	}
+
+	if err := s.checkAuthor(c, id); err != nil {
+		return models.Author{}, err
+	}
+
+	if err := s.checkPenName(c, id, auth.PenName); err != nil {
+		return models.Author{}, err
+	}
+
+	author, err := s.datastore.Update(c, id, auth, version)
 	if err != nil {
+		return models.Author{}, versionError(err, id, version)
+	}
+
+	return author, nil
+}
+
+// Patch Author details, only the given fields are updated
+func (s Service) Patch(c *gofr.Context, id int, patch models.AuthorPatch, version int) (models.Author, error) {
+	// Checking invalid id
+	if id <= 0 {
+		return models.Author{}, errInvalidID
+	}
+
+	if err := s.validateAuthor(patch); err != nil {
 		return models.Author{}, err
 	}
 
+	if err := s.checkAuthor(c, id); err != nil {
+		return models.Author{}, err
+	}
+
+	if patch.PenName != nil {
+		if err := s.checkPenName(c, id, *patch.PenName); err != nil {
+			return models.Author{}, err
+		}
+	}
+
+	author, err := s.datastore.Patch(c, id, patch, version)
+	if err != nil {
+		return models.Author{}, versionError(err, id, version)
+	}
+
 	return author, nil
 }
 
-// Delete Author by its ID
-func (s Service) Delete(c *gofr.Context, id int) (int, error) {
+// Delete Author by its ID, the books of the author are handled as per the policy. The author is locked, its
+// books handled and the author deleted in one transaction, so that the books are left alone when the author
+// is not deleted.
+func (s Service) Delete(c *gofr.Context, id int, policy models.DeletePolicy, version int) (int, error) {
 	// Checking for invalid id
 	if id <= 0 {
-		return 0, errors.Error("invalid id")
+		return 0, errInvalidID
 	}
 
-	// Checking author ID present or not
-	check := s.datastore.IsAuthorIDPresent(c, id)
+	var rowAffected int
 
-	if check {
-		return 0, errors.Error("author id is not valid")
-	}
+	err := s.unitOfWork.Do(c, func(tx datastore.Tx) error {
+		author, err := tx.Author().LockForUpdate(c, id)
+		if err != nil {
+			return notFound(err, id)
+		}
+
+		if version > 0 && author.Version != version {
+			return versionError(sql.ErrNoRows, id, version)
+		}
 
-	rowAffected, err := s.datastore.Delete(c, id)
+		if err := handleBooks(c, tx, id, policy); err != nil {
+			return err
+		}
+
+		// the locked author is still at the version checked above
+		rowAffected, err = tx.Author().Delete(c, id, 0)
+
+		return err
+	})
 	if err != nil {
 		return 0, err
 	}
@@ -82,10 +173,120 @@ func (s Service) Delete(c *gofr.Context, id int) (int, error) {
 	return rowAffected, nil
 }
 
-func isMissingFields(auth models.Author) bool {
-	if auth.FirstName == "" || auth.LastName == "" || auth.PenName == "" || auth.Dob == "" {
-		return true
+// handleBooks is to reject, delete or reassign the books of an author being deleted in the transaction
+func handleBooks(c *gofr.Context, tx datastore.Tx, id int, policy models.DeletePolicy) error {
+	switch policy.Books {
+	case "", models.DeleteReject:
+		count, err := tx.Book().Count(c, models.BookFilter{AuthorID: id})
+		if err != nil {
+			return err
+		}
+
+		if count > 0 {
+			return errors.Conflict{Code: errors.CodeAuthorHasBooks, Reason: fmt.Sprintf("author %v has %v books", id, count),
+				ResourceID: strconv.Itoa(id)}
+		}
+
+		return nil
+	case models.DeleteCascade:
+		_, err := tx.Book().DeleteByAuthorID(c, id)
+
+		return err
+	case models.DeleteReassign:
+		if policy.ReassignTo <= 0 || policy.ReassignTo == id {
+			return errors.InvalidParam{Code: errors.CodeInvalidParam, Params: []string{"reassignTo"}}
+		}
+
+		// the author the books are moved to is locked so that it is not deleted before the commit
+		if _, err := tx.Author().Lock(c, policy.ReassignTo); err != nil {
+			return notFound(err, policy.ReassignTo)
+		}
+
+		_, err := tx.Book().ReassignAuthor(c, id, policy.ReassignTo)
+
+		return err
+	default:
+		return errors.InvalidParam{Code: errors.CodeInvalidParam, Params: []string{"books"}}
+	}
+}
+
+// checkAuthor is to make sure that the author is stored, a failure of the DB is returned as it is
+func (s Service) checkAuthor(c *gofr.Context, id int) error {
+	exists, err := s.datastore.Exists(c, id)
+	if err != nil {
+		return err
+	}
+
+	if !exists {
+		return errors.NotFound{Entity: "Author", ID: strconv.Itoa(id)}
+	}
+
+	return nil
+}
+
+// notFound is to tell the sql.ErrNoRows of reading the author from any other failure
+func notFound(err error, id int) error {
+	if err == sql.ErrNoRows {
+		return errors.NotFound{Entity: "Author", ID: strconv.Itoa(id)}
+	}
+
+	return err
+}
+
+// versionError is to tell the sql.ErrNoRows of a datastore change from any other failure, the author is no
+// longer at the given version or, without one, it was deleted after it was checked
+func versionError(err error, id, version int) error {
+	switch {
+	case err != sql.ErrNoRows:
+		return err
+	case version > 0:
+		return errors.PreconditionFailed{Entity: "Author", ID: strconv.Itoa(id)}
+	default:
+		return errors.NotFound{Entity: "Author", ID: strconv.Itoa(id)}
+	}
+}
+
+// checkPenName is to make sure that the pen name is not taken by an author other than the one with the given id
+func (s Service) checkPenName(c *gofr.Context, id int, penName string) error {
+	author, err := s.datastore.GetByPenName(c, penName)
+
+	switch {
+	case err == sql.ErrNoRows:
+		return nil
+	case err != nil:
+		return err
+	case author.AuthID != id:
+		return errors.Conflict{Code: errors.CodePenNameTaken,
+			Reason:     fmt.Sprintf("pen name %v is taken by author %v", author.PenName, author.AuthID),
+			ResourceID: strconv.Itoa(author.AuthID)}
+	}
+
+	return nil
+}
+
+// isValidDob is to check that the author is at least minAge and at most maxAge years old today
+func (s Service) isValidDob(dob models.Date) bool {
+	now := s.now().UTC()
+	today := models.NewDate(now.Year(), now.Month(), now.Day()).Time()
+
+	return !dob.Time().After(today.AddDate(-minAge, 0, 0)) && !dob.Time().Before(today.AddDate(-maxAge, 0, 0))
+}
+
+// validateAuthor is to check an Author or AuthorPatch payload, reporting every offending field
+func (s Service) validateAuthor(payload interface{}) error {
+	checks := map[string]validate.Check{
+		"born": func(value string) string {
+			if dob, err := models.ParseDate(value); err == nil && !s.isValidDob(dob) {
+				return errors.ReasonOutOfRange
+			}
+
+			return ""
+		},
+	}
+
+	if fields := validate.Struct(payload, checks); len(fields) > 0 {
+		return errors.Validation{Code: errors.CodeInvalidField, Fields: fields, Reason: "invalid author"}
 	}
 
-	return false
+	return nil
 }
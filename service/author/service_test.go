@@ -1,50 +1,124 @@
 package author
 
 import (
-	"developer.zopsmart.com/go/gofr/pkg/errors"
+	"database/sql"
+	gofrErrors "developer.zopsmart.com/go/gofr/pkg/errors"
 	"developer.zopsmart.com/go/gofr/pkg/gofr"
 	"log"
 	"reflect"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/golang/mock/gomock"
 
-	"Three-Layer-Architecture/datastore"
-	"Three-Layer-Architecture/models"
+	"mytest/datastore"
+	"mytest/models"
+	"mytest/models/errors"
 )
 
+// invalidAuthor is the validation error of an author payload with the given offending fields
+func invalidAuthor(fields ...errors.FieldError) error {
+	return errors.Validation{Code: errors.CodeInvalidField, Fields: fields, Reason: "invalid author"}
+}
+
+// clock is the fixed time the date of birth is checked against in the tests
+func clock() time.Time {
+	return time.Date(2024, 5, 10, 15, 0, 0, 0, time.UTC)
+}
+
+// ruskin is the only author of the tests holding a pen name
+var ruskin = models.Author{AuthID: 7, FirstName: "Ruskin", LastName: "Bond", Dob: models.NewDate(1934, 5, 19),
+	PenName: "Ruskin"}
+
+// getByPenName is the stub of the datastore lookup of an author by its pen name whatever the case
+func getByPenName(c *gofr.Context, penName string) (models.Author, error) {
+	if strings.EqualFold(penName, ruskin.PenName) {
+		return ruskin, nil
+	}
+
+	return models.Author{}, sql.ErrNoRows
+}
+
+// newMockUnitOfWork is to get a unit of work running the work on the given datastores
+func newMockUnitOfWork(ctr *gomock.Controller, book datastore.Book, author datastore.Author) *datastore.MockUnitOfWork {
+	tx := datastore.NewMockTx(ctr)
+	tx.EXPECT().Book().Return(book).AnyTimes()
+	tx.EXPECT().Author().Return(author).AnyTimes()
+
+	unitOfWork := datastore.NewMockUnitOfWork(ctr)
+	unitOfWork.EXPECT().Do(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(c *gofr.Context, work func(tx datastore.Tx) error) error {
+			return work(tx)
+		}).AnyTimes()
+
+	return unitOfWork
+}
+
 // TestAuthor_Post function is to test post author details for valid conditions
 func TestAuthor_Post(t *testing.T) {
+	badDob, _ := models.ParseDate("31/04/2001")
+
 	testcases := []struct {
 		desc     string
 		req      models.Author
 		response models.Author
 		err      error
 	}{
-		{desc: "valid details", req: models.Author{AuthID: 1, FirstName: "Chetan", LastName: "Bhagat", Dob: "06/04/2001", PenName: "Chetan"},
-			response: models.Author{AuthID: 1, FirstName: "Chetan", LastName: "Bhagat", Dob: "06/04/2001", PenName: "Chetan"}},
-		{desc: "missing first name", req: models.Author{AuthID: 1, LastName: "Bhagat", Dob: "06/04/2001", PenName: "Chetan"},
-			err: errors.Error("missing fields")},
-		{desc: "invalid id", req: models.Author{AuthID: -11, FirstName: "Chetan", LastName: "Bhagat", Dob: "06/04/2001", PenName: "Chetan"},
-			err: errors.Error("invalid id")},
-		{desc: "missing last name", req: models.Author{AuthID: 1, FirstName: "Chetan", Dob: "06/04/2001", PenName: "Chetan"},
-			err: errors.Error("missing fields")},
-		{desc: "missing dob", req: models.Author{AuthID: 1, FirstName: "Chetan", LastName: "Bhagat", PenName: "Chetan"},
-			err: errors.Error("missing fields")},
-		{desc: "missing penName", req: models.Author{AuthID: 1, FirstName: "Chetan", LastName: "Bhagat", Dob: "06/04/2001"},
-			err: errors.Error("missing fields")},
-		{desc: "error in post", req: models.Author{AuthID: 12, FirstName: "Chetan", LastName: "Bhagat", Dob: "06/04/2001", PenName: "Bhagat"}, response: models.Author{},
-			err: errors.Error("error in post")},
+		{desc: "valid details", req: models.Author{FirstName: "Chetan", LastName: "Bhagat",
+			Dob: models.NewDate(2001, 4, 6), PenName: "Chetan"},
+			response: models.Author{AuthID: 1, FirstName: "Chetan", LastName: "Bhagat",
+				Dob: models.NewDate(2001, 4, 6), PenName: "Chetan"}},
+		{desc: "missing first name", req: models.Author{LastName: "Bhagat", Dob: models.NewDate(2001, 4, 6),
+			PenName: "Chetan"},
+			err: invalidAuthor(errors.FieldError{Field: "firstName", Reason: errors.ReasonMissing})},
+		{desc: "missing last name", req: models.Author{FirstName: "Chetan", Dob: models.NewDate(2001, 4, 6),
+			PenName: "Chetan"},
+			err: invalidAuthor(errors.FieldError{Field: "lastName", Reason: errors.ReasonMissing})},
+		{desc: "missing dob", req: models.Author{FirstName: "Chetan", LastName: "Bhagat", PenName: "Chetan"},
+			err: invalidAuthor(errors.FieldError{Field: "dob", Reason: errors.ReasonMissing})},
+		{desc: "missing penName", req: models.Author{FirstName: "Chetan", LastName: "Bhagat",
+			Dob: models.NewDate(2001, 4, 6)},
+			err: invalidAuthor(errors.FieldError{Field: "penName", Reason: errors.ReasonMissing})},
+		{desc: "every field reported", req: models.Author{FirstName: strings.Repeat("a", 51), Dob: badDob},
+			err: invalidAuthor(errors.FieldError{Field: "firstName", Reason: errors.ReasonTooLong},
+				errors.FieldError{Field: "lastName", Reason: errors.ReasonMissing},
+				errors.FieldError{Field: "dob", Reason: errors.ReasonBadFormat},
+				errors.FieldError{Field: "penName", Reason: errors.ReasonMissing})},
+		{desc: "born today", req: models.Author{FirstName: "Chetan", LastName: "Bhagat",
+			Dob: models.NewDate(2024, 5, 10), PenName: "Chetan"},
+			err: invalidAuthor(errors.FieldError{Field: "dob", Reason: errors.ReasonOutOfRange})},
+		{desc: "youngest", req: models.Author{FirstName: "Chetan", LastName: "Bhagat",
+			Dob: models.NewDate(2014, 5, 10), PenName: "Chetan"},
+			response: models.Author{AuthID: 2, FirstName: "Chetan", LastName: "Bhagat",
+				Dob: models.NewDate(2014, 5, 10), PenName: "Chetan"}},
+		{desc: "younger than 10 years", req: models.Author{FirstName: "Chetan", LastName: "Bhagat",
+			Dob: models.NewDate(2014, 5, 11), PenName: "Chetan"},
+			err: invalidAuthor(errors.FieldError{Field: "dob", Reason: errors.ReasonOutOfRange})},
+		{desc: "oldest", req: models.Author{FirstName: "Chetan", LastName: "Bhagat",
+			Dob: models.NewDate(1894, 5, 10), PenName: "Chetan"},
+			response: models.Author{AuthID: 3, FirstName: "Chetan", LastName: "Bhagat",
+				Dob: models.NewDate(1894, 5, 10), PenName: "Chetan"}},
+		{desc: "older than 130 years", req: models.Author{FirstName: "Chetan", LastName: "Bhagat",
+			Dob: models.NewDate(1894, 5, 9), PenName: "Chetan"},
+			err: invalidAuthor(errors.FieldError{Field: "dob", Reason: errors.ReasonOutOfRange})},
+		{desc: "pen name taken", req: models.Author{FirstName: "Chetan", LastName: "Bhagat",
+			Dob: models.NewDate(2001, 4, 6), PenName: "RUSKIN"}, err: errors.Conflict{Code: errors.CodePenNameTaken,
+			Reason: "pen name Ruskin is taken by author 7", ResourceID: "7"}},
+		{desc: "error in post", req: models.Author{FirstName: "Chetan", LastName: "Bhagat",
+			Dob: models.NewDate(2001, 4, 6), PenName: "Bhagat"}, response: models.Author{},
+			err: gofrErrors.Error("error in post")},
 	}
 
 	ctr := gomock.NewController(t)
 	mockAuthor := datastore.NewMockAuthor(ctr)
-	service := New(mockAuthor)
+	service := New(mockAuthor, datastore.NewMockUnitOfWork(ctr), clock)
 
 	for i, v := range testcases {
 		var c *gofr.Context
 
 		mockAuthor.EXPECT().Post(c, v.req).Return(v.response, v.err).AnyTimes()
+		mockAuthor.EXPECT().GetByPenName(c, v.req.PenName).DoAndReturn(getByPenName).AnyTimes()
 
 		resp, err := service.Post(c, v.req)
 
@@ -58,6 +132,78 @@ func TestAuthor_Post(t *testing.T) {
 	}
 }
 
+// TestAuthor_GetAll function is to test get all authors
+func TestAuthor_GetAll(t *testing.T) {
+	testcases := []struct {
+		desc string
+		resp []models.Author
+		err  error
+	}{
+		{desc: "valid details", resp: []models.Author{{AuthID: 1, FirstName: "Chetan", LastName: "Bhagat",
+			Dob: models.NewDate(2001, 4, 6), PenName: "Chetan"}}},
+		{desc: "error in get all", resp: []models.Author{}, err: gofrErrors.Error("error in get all")},
+	}
+
+	for i, v := range testcases {
+		var c *gofr.Context
+
+		ctr := gomock.NewController(t)
+		mockAuthor := datastore.NewMockAuthor(ctr)
+		service := New(mockAuthor, datastore.NewMockUnitOfWork(ctr), clock)
+
+		mockAuthor.EXPECT().GetAll(c).Return(v.resp, v.err).AnyTimes()
+
+		resp, err := service.GetAll(c)
+
+		if !reflect.DeepEqual(resp, v.resp) {
+			t.Errorf("desc : %v ,[TEST%d]Failed. Got %v\tExpected %v\n", v.desc, i+1, resp, v.resp)
+		}
+
+		if !reflect.DeepEqual(err, v.err) {
+			t.Errorf("desc : %v ,[TEST%d]Failed. Got %v\tExpected %v\n", v.desc, i+1, err, v.err)
+		}
+	}
+}
+
+// TestAuthor_GetByID function is to test get author by id
+func TestAuthor_GetByID(t *testing.T) {
+	testcases := []struct {
+		desc        string
+		id          int
+		resp        models.Author
+		checkAuthor bool
+		getErr      error
+		err         error
+	}{
+		{desc: "valid", id: 1, resp: models.Author{AuthID: 1, FirstName: "Chetan", LastName: "Bhagat",
+			Dob: models.NewDate(2001, 4, 6), PenName: "Chetan"}},
+		{desc: "invalid id", id: -11, err: errInvalidID},
+		{desc: "author not found", id: 2, checkAuthor: true, err: errors.NotFound{Entity: "Author", ID: "2"}},
+		{desc: "error in get", id: 3, getErr: gofrErrors.Error("error in get"), err: gofrErrors.Error("error in get")},
+	}
+
+	ctr := gomock.NewController(t)
+	mockAuthor := datastore.NewMockAuthor(ctr)
+	service := New(mockAuthor, datastore.NewMockUnitOfWork(ctr), clock)
+
+	for i, v := range testcases {
+		var c *gofr.Context
+
+		mockAuthor.EXPECT().Exists(c, v.id).Return(!v.checkAuthor, nil).AnyTimes()
+		mockAuthor.EXPECT().GetByID(c, v.id).Return(v.resp, v.getErr).AnyTimes()
+
+		resp, err := service.GetByID(c, v.id)
+
+		if !reflect.DeepEqual(resp, v.resp) {
+			t.Errorf("desc : %v ,[TEST%d]Failed. Got %v\tExpected %v\n", v.desc, i+1, resp, v.resp)
+		}
+
+		if !reflect.DeepEqual(err, v.err) {
+			t.Errorf("desc : %v ,[TEST%d]Failed. Got %v\tExpected %v\n", v.desc, i+1, err, v.err)
+		}
+	}
+}
+
 // TestAuthor_PutValidID function is to test update valid author details
 func TestAuthor_PutValidID(t *testing.T) {
 	testcases := []struct {
@@ -69,26 +215,29 @@ func TestAuthor_PutValidID(t *testing.T) {
 		err         error
 	}{
 		{desc: "valid", id: 1, req: models.Author{AuthID: 1, FirstName: "Rajan", LastName: "Sharma",
-			Dob: "26/04/2001", PenName: "Rajan"}, resp: models.Author{AuthID: 1, FirstName: "Rajan", LastName: "Sharma",
-			Dob: "26/04/2001", PenName: "Rajan"}, checkAuthor: false},
+			Dob: models.NewDate(2001, 4, 26), PenName: "Rajan"}, resp: models.Author{AuthID: 1, FirstName: "Rajan",
+			LastName: "Sharma",
+			Dob:      models.NewDate(2001, 4, 26), PenName: "Rajan"}, checkAuthor: false},
 		{desc: "missing fields", id: 1, req: models.Author{AuthID: 1, FirstName: "Rajan", LastName: "Sharma",
-			Dob: "26/04/2001", PenName: ""}, checkAuthor: false},
-		{desc: "invalid id", id: -11, err: errors.Error("invalid id"), checkAuthor: false},
+			Dob: models.NewDate(2001, 4, 26), PenName: ""}, checkAuthor: false},
+		{desc: "invalid id", id: -11, err: errInvalidID, checkAuthor: false},
 		{desc: "error in Update", id: 11, req: models.Author{AuthID: 14, FirstName: "Rajan", LastName: "Sharma",
-			Dob: "26/04/2001", PenName: "Rajan"}, err: errors.Error("error in update"), checkAuthor: false},
+			Dob: models.NewDate(2001, 4, 26), PenName: "Rajan"}, err: gofrErrors.Error("error in update"),
+			checkAuthor: false},
 	}
 
 	ctr := gomock.NewController(t)
 	mockAuthor := datastore.NewMockAuthor(ctr)
-	service := New(mockAuthor)
+	service := New(mockAuthor, datastore.NewMockUnitOfWork(ctr), clock)
 
 	for i, v := range testcases {
 		var c *gofr.Context
 
-		mockAuthor.EXPECT().Update(c, v.id, v.req).Return(v.resp, v.err).AnyTimes()
-		mockAuthor.EXPECT().IsAuthorIDPresent(c, v.id).Return(v.checkAuthor).AnyTimes()
+		mockAuthor.EXPECT().Update(c, v.id, v.req, 0).Return(v.resp, v.err).AnyTimes()
+		mockAuthor.EXPECT().Exists(c, v.id).Return(!v.checkAuthor, nil).AnyTimes()
+		mockAuthor.EXPECT().GetByPenName(c, v.req.PenName).DoAndReturn(getByPenName).AnyTimes()
 
-		resp, err := service.Update(c, v.id, v.req)
+		resp, err := service.Update(c, v.id, v.req, 0)
 
 		if !reflect.DeepEqual(resp, v.resp) {
 			t.Errorf("desc : %v ,[TEST%d]Failed. Got %v\tExpected %v\n", v.desc, i+1, resp, v.resp)
@@ -111,20 +260,21 @@ func TestAuthor_PutInvalidID(t *testing.T) {
 		err         error
 	}{
 		{desc: "isAuthorPresent", req: models.Author{AuthID: 12, FirstName: "shiv", LastName: "chandra",
-			Dob: "01/01/2001", PenName: "shiv"}, id: 1, checkAuthor: true},
+			Dob: models.NewDate(2001, 1, 1), PenName: "shiv"}, id: 1, checkAuthor: true},
 	}
 
 	ctr := gomock.NewController(t)
 	mockAuthor := datastore.NewMockAuthor(ctr)
-	service := New(mockAuthor)
+	service := New(mockAuthor, datastore.NewMockUnitOfWork(ctr), clock)
 
 	for i, v := range testcases {
 		var c *gofr.Context
 
-		mockAuthor.EXPECT().Update(c, v.id, v.req).Return(v.resp, v.err).AnyTimes()
-		mockAuthor.EXPECT().IsAuthorIDPresent(c, v.id).Return(v.checkAuthor).AnyTimes()
+		mockAuthor.EXPECT().Update(c, v.id, v.req, 0).Return(v.resp, v.err).AnyTimes()
+		mockAuthor.EXPECT().Exists(c, v.id).Return(!v.checkAuthor, nil).AnyTimes()
+		mockAuthor.EXPECT().GetByPenName(c, v.req.PenName).DoAndReturn(getByPenName).AnyTimes()
 
-		resp, err := service.Update(c, v.id, v.req)
+		resp, err := service.Update(c, v.id, v.req, 0)
 
 		if !reflect.DeepEqual(resp, v.resp) {
 			t.Errorf("desc : %v ,[TEST%d]Failed. Got %v\tExpected %v\n", v.desc, i+1, resp, v.resp)
@@ -136,37 +286,181 @@ func TestAuthor_PutInvalidID(t *testing.T) {
 	}
 }
 
-// TestAuthor_DeleteValidID function is to test for remove author
-func TestAuthor_DeleteValidID(t *testing.T) {
+// TestAuthor_Patch function is to test partial update of author details
+func TestAuthor_Patch(t *testing.T) {
+	firstName, empty, penName := "Rajan", "", "ruskin"
+	future := models.NewDate(2030, 1, 1)
+
 	testcases := []struct {
 		desc        string
 		id          int
-		rowAffected int
+		patch       models.AuthorPatch
+		resp        models.Author
 		checkAuthor bool
+		patchErr    error
 		err         error
 	}{
-		{desc: "valid", id: 1, rowAffected: 1},
-		{desc: "invalid id", id: -11, err: errors.Error("invalid id")},
-		{desc: "error in delete", id: 12, err: errors.Error("error in delete")},
+		{desc: "valid", id: 1, patch: models.AuthorPatch{FirstName: &firstName}, resp: models.Author{AuthID: 1,
+			FirstName: "Rajan", LastName: "Bhagat", Dob: models.NewDate(2001, 4, 6), PenName: "Chetan"}},
+		{desc: "empty patch", id: 2, resp: models.Author{AuthID: 2, FirstName: "Chetan", LastName: "Bhagat",
+			Dob: models.NewDate(2001, 4, 6), PenName: "Chetan"}},
+		{desc: "invalid id", id: -1, err: errInvalidID},
+		{desc: "emptied field", id: 3, patch: models.AuthorPatch{PenName: &empty},
+			err: invalidAuthor(errors.FieldError{Field: "penName", Reason: errors.ReasonMissing})},
+		{desc: "dob in future", id: 3, patch: models.AuthorPatch{Dob: &future},
+			err: invalidAuthor(errors.FieldError{Field: "dob", Reason: errors.ReasonOutOfRange})},
+		{desc: "own pen name", id: 7, patch: models.AuthorPatch{PenName: &penName}, resp: ruskin},
+		{desc: "pen name taken", id: 3, patch: models.AuthorPatch{PenName: &penName}, err: errors.Conflict{
+			Code: errors.CodePenNameTaken, Reason: "pen name Ruskin is taken by author 7", ResourceID: "7"}},
+		{desc: "author not found", id: 4, patch: models.AuthorPatch{FirstName: &firstName}, checkAuthor: true,
+			err: errors.NotFound{Entity: "Author", ID: "4"}},
+		{desc: "error in patch", id: 5, patch: models.AuthorPatch{FirstName: &firstName},
+			patchErr: gofrErrors.Error("error in patch"), err: gofrErrors.Error("error in patch")},
 	}
 
 	ctr := gomock.NewController(t)
 	mockAuthor := datastore.NewMockAuthor(ctr)
-	service := New(mockAuthor)
+	service := New(mockAuthor, datastore.NewMockUnitOfWork(ctr), clock)
 
 	for i, v := range testcases {
 		var c *gofr.Context
-		mockAuthor.EXPECT().Delete(c, v.id).Return(v.rowAffected, v.err).AnyTimes()
-		mockAuthor.EXPECT().IsAuthorIDPresent(c, v.id).Return(v.checkAuthor).AnyTimes()
 
-		resp, err := service.Delete(c, v.id)
+		mockAuthor.EXPECT().Patch(c, v.id, v.patch, 0).Return(v.resp, v.patchErr).AnyTimes()
+		mockAuthor.EXPECT().GetByPenName(c, gomock.Any()).DoAndReturn(getByPenName).AnyTimes()
+		mockAuthor.EXPECT().Exists(c, v.id).Return(!v.checkAuthor, nil).AnyTimes()
+
+		resp, err := service.Patch(c, v.id, v.patch, 0)
+
+		if !reflect.DeepEqual(resp, v.resp) {
+			t.Errorf("desc : %v ,[TEST%d]Failed. Got %v\tExpected %v\n", v.desc, i+1, resp, v.resp)
+		}
+
+		if !reflect.DeepEqual(err, v.err) {
+			t.Errorf("desc : %v ,[TEST%d]Failed. Got %v\tExpected %v\n", v.desc, i+1, err, v.err)
+		}
+	}
+}
+
+// TestAuthor_DeletedAfterCheck function is to test that an author deleted after it was checked is not found,
+// and one changed meanwhile fails the version it was changed on
+func TestAuthor_DeletedAfterCheck(t *testing.T) {
+	auth := models.Author{FirstName: "Chetan", LastName: "Bhagat", Dob: models.NewDate(2001, 4, 6), PenName: "Chetan"}
+	firstName := "Rajan"
+	patch := models.AuthorPatch{FirstName: &firstName}
+
+	testcases := []struct {
+		desc    string
+		version int
+		err     error
+	}{
+		{desc: "deleted", err: errors.NotFound{Entity: "Author", ID: "1"}},
+		{desc: "changed", version: 3, err: errors.PreconditionFailed{Entity: "Author", ID: "1"}},
+	}
+
+	for i, v := range testcases {
+		var c *gofr.Context
+
+		ctr := gomock.NewController(t)
+		mockAuthor := datastore.NewMockAuthor(ctr)
+		service := New(mockAuthor, datastore.NewMockUnitOfWork(ctr), clock)
+
+		mockAuthor.EXPECT().Exists(c, 1).Return(true, nil).Times(2)
+		mockAuthor.EXPECT().GetByPenName(c, auth.PenName).Return(models.Author{}, sql.ErrNoRows)
+		mockAuthor.EXPECT().Update(c, 1, auth, v.version).Return(models.Author{}, sql.ErrNoRows)
+		mockAuthor.EXPECT().Patch(c, 1, patch, v.version).Return(models.Author{}, sql.ErrNoRows)
+
+		if _, err := service.Update(c, 1, auth, v.version); !reflect.DeepEqual(err, v.err) {
+			t.Errorf("desc : %v update ,[TEST%d]Failed. Got %v\tExpected %v\n", v.desc, i+1, err, v.err)
+		}
+
+		if _, err := service.Patch(c, 1, patch, v.version); !reflect.DeepEqual(err, v.err) {
+			t.Errorf("desc : %v patch ,[TEST%d]Failed. Got %v\tExpected %v\n", v.desc, i+1, err, v.err)
+		}
+	}
+}
+
+// TestAuthor_DeleteValidID function is to test for remove author
+func TestAuthor_DeleteValidID(t *testing.T) {
+	conflict := errors.Conflict{Code: errors.CodeAuthorHasBooks, Reason: "author 4 has 2 books", ResourceID: "4"}
+
+	testcases := []struct {
+		desc        string
+		id          int
+		policy      models.DeletePolicy
+		version     int
+		stored      int
+		books       int
+		checkTarget bool
+		rowAffected int
+		lockErr     error
+		deleteErr   error
+		bookErr     error
+		err         error
+	}{
+		{desc: "valid", id: 1, rowAffected: 1},
+		{desc: "author not found", id: 3, lockErr: sql.ErrNoRows, err: errors.NotFound{Entity: "Author", ID: "3"}},
+		{desc: "error in lock", id: 3, lockErr: gofrErrors.Error("error in lock"), err: gofrErrors.Error("error in lock")},
+		{desc: "reject without books", id: 2, policy: models.DeletePolicy{Books: models.DeleteReject}, rowAffected: 1},
+		{desc: "invalid id", id: -11, err: errInvalidID},
+		{desc: "error in delete", id: 12, deleteErr: gofrErrors.Error("error in delete"), err: gofrErrors.Error("error in delete")},
+		{desc: "reject with books", id: 4, books: 2, err: conflict},
+		{desc: "error in count", id: 5, bookErr: gofrErrors.Error("error in count"), err: gofrErrors.Error("error in count")},
+		{desc: "cascade", id: 6, policy: models.DeletePolicy{Books: models.DeleteCascade}, books: 2, rowAffected: 1},
+		{desc: "error in cascade", id: 7, policy: models.DeletePolicy{Books: models.DeleteCascade},
+			bookErr: gofrErrors.Error("error in cascade"), err: gofrErrors.Error("error in cascade")},
+		{desc: "reassign", id: 8, policy: models.DeletePolicy{Books: models.DeleteReassign, ReassignTo: 9}, books: 2,
+			rowAffected: 1},
+		{desc: "reassign to missing author", id: 10, policy: models.DeletePolicy{Books: models.DeleteReassign, ReassignTo: 11},
+			checkTarget: true, err: errors.NotFound{Entity: "Author", ID: "11"}},
+		{desc: "reassign to itself", id: 12, policy: models.DeletePolicy{Books: models.DeleteReassign, ReassignTo: 12},
+			err: errors.InvalidParam{Code: errors.CodeInvalidParam, Params: []string{"reassignTo"}}},
+		{desc: "reassign without target", id: 13, policy: models.DeletePolicy{Books: models.DeleteReassign},
+			err: errors.InvalidParam{Code: errors.CodeInvalidParam, Params: []string{"reassignTo"}}},
+		{desc: "error in reassign", id: 14, policy: models.DeletePolicy{Books: models.DeleteReassign, ReassignTo: 9},
+			bookErr: gofrErrors.Error("error in reassign"), err: gofrErrors.Error("error in reassign")},
+		{desc: "invalid policy", id: 15, policy: models.DeletePolicy{Books: "orphan"},
+			err: errors.InvalidParam{Code: errors.CodeInvalidParam, Params: []string{"books"}}},
+		{desc: "at the version", id: 16, version: 2, stored: 2, rowAffected: 1},
+		{desc: "version moved on", id: 17, policy: models.DeletePolicy{Books: models.DeleteCascade}, version: 2,
+			stored: 3, books: 2, err: errors.PreconditionFailed{Entity: "Author", ID: "17"}},
+	}
+
+	for i, v := range testcases {
+		var c *gofr.Context
+
+		ctr := gomock.NewController(t)
+		mockAuthor := datastore.NewMockAuthor(ctr)
+		mockBook := datastore.NewMockBook(ctr)
+		service := New(mockAuthor, newMockUnitOfWork(ctr, mockBook, mockAuthor), clock)
+
+		targetErr := error(nil)
+		if v.checkTarget {
+			targetErr = sql.ErrNoRows
+		}
+
+		mockAuthor.EXPECT().LockForUpdate(c, v.id).Return(models.Author{AuthID: v.id, Version: v.stored}, v.lockErr).
+			AnyTimes()
+		mockAuthor.EXPECT().Lock(c, v.policy.ReassignTo).Return(models.Author{AuthID: v.policy.ReassignTo}, targetErr).
+			AnyTimes()
+		mockAuthor.EXPECT().Delete(c, v.id, 0).Return(v.rowAffected, v.deleteErr).AnyTimes()
+		mockBook.EXPECT().Count(c, models.BookFilter{AuthorID: v.id}).Return(v.books, v.bookErr).AnyTimes()
+		// the books are left alone when the version has moved on
+		cascades := 1
+		if v.stored != v.version {
+			cascades = 0
+		}
+
+		mockBook.EXPECT().DeleteByAuthorID(c, v.id).Return(v.books, v.bookErr).MaxTimes(cascades)
+		mockBook.EXPECT().ReassignAuthor(c, v.id, v.policy.ReassignTo).Return(v.books, v.bookErr).AnyTimes()
+
+		resp, err := service.Delete(c, v.id, v.policy, v.version)
 
 		if !reflect.DeepEqual(resp, v.rowAffected) {
 			t.Errorf("desc : %v ,[TEST%d]Failed. Got %v\tExpected %v\n", v.desc, i+1, resp, v.rowAffected)
 		}
 
-		if err != nil {
-			log.Printf("desc : %v ,[TEST%d]Failed. Got %v\tExpected %v\n", v.desc, i+1, err, v.err)
+		if !reflect.DeepEqual(err, v.err) {
+			t.Errorf("desc : %v ,[TEST%d]Failed. Got %v\tExpected %v\n", v.desc, i+1, err, v.err)
 		}
 	}
 }
@@ -185,14 +479,20 @@ func TestAuthor_DeleteInvalidID(t *testing.T) {
 
 	ctr := gomock.NewController(t)
 	mockAuthor := datastore.NewMockAuthor(ctr)
-	service := New(mockAuthor)
+	service := New(mockAuthor, newMockUnitOfWork(ctr, datastore.NewMockBook(ctr), mockAuthor), clock)
 
 	for i, v := range testcases {
 		var c *gofr.Context
-		mockAuthor.EXPECT().Delete(c, v.id).Return(v.rowAffected, v.err).AnyTimes()
-		mockAuthor.EXPECT().IsAuthorIDPresent(c, v.id).Return(v.checkAuthor).AnyTimes()
 
-		resp, err := service.Delete(c, v.id)
+		lockErr := error(nil)
+		if v.checkAuthor {
+			lockErr = sql.ErrNoRows
+		}
+
+		mockAuthor.EXPECT().Delete(c, v.id, 0).Return(v.rowAffected, v.err).AnyTimes()
+		mockAuthor.EXPECT().LockForUpdate(c, v.id).Return(models.Author{}, lockErr).AnyTimes()
+
+		resp, err := service.Delete(c, v.id, models.DeletePolicy{}, 0)
 
 		if !reflect.DeepEqual(resp, v.rowAffected) {
 			t.Errorf("desc : %v ,[TEST%d]Failed. Got %v\tExpected %v\n", v.desc, i+1, resp, v.rowAffected)
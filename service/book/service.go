@@ -1,70 +1,94 @@
 package book
 
 import (
-	"developer.zopsmart.com/go/gofr/pkg/errors"
 	"developer.zopsmart.com/go/gofr/pkg/gofr"
 
 	"mytest/datastore"
 	"mytest/models"
+	"mytest/models/errors"
+	"mytest/models/validate"
 
+	"database/sql"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
 	"strconv"
-	"strings"
+	"time"
 )
 
+const (
+	defaultLimit = 20
+	maxLimit     = 100
+	// firstPublishedYear is the earliest year a book of the catalog can be published in
+	firstPublishedYear = 1880
+)
+
+var errInvalidID = errors.InvalidParam{Code: errors.CodeInvalidParam, Params: []string{"id"}}
+
 type Service struct {
-	datastoreBook   datastore.Book
-	datastoreAuthor datastore.Author
+	datastoreBook      datastore.Book
+	datastoreAuthor    datastore.Author
+	datastorePublisher datastore.Publisher
+	// unitOfWork is to store a book in the same transaction its author is checked in
+	unitOfWork datastore.UnitOfWork
+	// now is the clock the published date is checked against
+	now func() time.Time
+	// horizonDays is how far in the future an announced book can be published
+	horizonDays int
+	// duplicateRule is how a book is told to be a duplicate of another stored one
+	duplicateRule string
 }
 
-func New(book datastore.Book, author datastore.Author) Service {
-	return Service{book, author}
+// New is to build the book Service, a book can be published up to horizonDays after the day of the clock
+// and a book is rejected when it is a duplicate of another stored one as per the duplicateRule
+func New(book datastore.Book, author datastore.Author, publisher datastore.Publisher, unitOfWork datastore.UnitOfWork,
+	now func() time.Time, horizonDays int, duplicateRule string) Service {
+	return Service{book, author, publisher, unitOfWork, now, horizonDays, duplicateRule}
 }
 
-// Post method is to post Book details
+// Post method is to post Book details, the BookID is assigned by the datastore. The author is checked and
+// the book inserted in one transaction, so that the author cannot be deleted in between.
 func (s Service) Post(c *gofr.Context, book *models.Book) (models.Book, error) {
-	if book.BookID <= 0 {
-		return models.Book{}, errors.Error("invalid id")
+	if err := s.validateBook(c, book); err != nil {
+		return models.Book{}, err
 	}
 
-	// missing book fields
-	if isBookFieldsMissing(book) {
-		return models.Book{}, errors.Error("missing book fields")
-	}
+	book.ISBN = normalizeISBN(book.ISBN)
 
-	if !isValidPublishedDate(book.PublishedDate) {
-		return models.Book{}, errors.Error("invalid publishedDate")
-	}
+	var created models.Book
 
-	if !isValidPublication(book.Publication) {
-		return models.Book{}, errors.Error("invalid publication")
-	}
+	err := s.unitOfWork.Do(c, func(tx datastore.Tx) error {
+		var err error
 
-	var err error
+		book.Auth, err = lockAuthor(c, tx.Author(), book.AuthorID)
+		if err != nil {
+			return err
+		}
 
-	book.Auth, err = s.datastoreAuthor.IncludeAuthor(c, book.AuthorID)
-	if err != nil {
-		return models.Book{}, errors.EntityNotFound{Entity: "Author", ID: strconv.Itoa(book.AuthorID)}
-	}
+		if err := s.checkDuplicate(c, tx.Book(), 0, book); err != nil {
+			return err
+		}
 
-	_, err = s.datastoreBook.Post(c, book)
+		created, err = tx.Book().Post(c, book)
+
+		return err
+	})
 	if err != nil {
 		return models.Book{}, err
 	}
 
-	return *book, nil
+	return created, nil
 }
 
-// GetByID method is to get Book details by id
-func (s Service) GetByID(c *gofr.Context, id int) (models.Book, error) {
+// GetByID method is to get Book details by id along with author details
+func (s Service) GetByID(c *gofr.Context, id int, includeAuthor string) (models.Book, error) {
 	// Checking invalid id
 	if id <= 0 {
-		return models.Book{}, errors.Error("invalid id")
+		return models.Book{}, errInvalidID
 	}
 
-	check := s.datastoreBook.IsBookPresent(c, id)
-
-	if check {
-		return models.Book{}, errors.EntityNotFound{Entity: "Book", ID: strconv.Itoa(id)}
+	if err := checkBook(c, s.datastoreBook, id); err != nil {
+		return models.Book{}, err
 	}
 
 	book, err := s.datastoreBook.GetByID(c, id)
@@ -72,131 +96,563 @@ func (s Service) GetByID(c *gofr.Context, id int) (models.Book, error) {
 		return models.Book{}, err
 	}
 
+	if includeAuthor == "true" {
+		books := []models.Book{book}
+
+		if err := s.includeAuthors(c, books); err != nil {
+			return models.Book{}, err
+		}
+
+		book = books[0]
+	}
+
 	return book, nil
 }
 
-// Update method is to update Book details
-func (s Service) Update(c *gofr.Context, id int, book *models.Book) (models.Book, error) {
+// GetByISBN method is to get Book details by its ISBN-10 or ISBN-13 along with author details
+func (s Service) GetByISBN(c *gofr.Context, isbn, includeAuthor string) (models.Book, error) {
+	normalized, err := models.ParseISBN(isbn)
+	if err != nil {
+		return models.Book{}, errors.InvalidParam{Code: errors.CodeInvalidParam, Params: []string{"isbn"}}
+	}
+
+	book, err := s.datastoreBook.GetByISBN(c, normalized)
+
+	switch {
+	case err == sql.ErrNoRows:
+		return models.Book{}, errors.NotFound{Entity: "Book", ID: string(normalized)}
+	case err != nil:
+		return models.Book{}, err
+	}
+
+	if includeAuthor == "true" {
+		books := []models.Book{book}
+
+		if err := s.includeAuthors(c, books); err != nil {
+			return models.Book{}, err
+		}
+
+		book = books[0]
+	}
+
+	return book, nil
+}
+
+// Update method is to update Book details, the stored book is returned along with author details. When a
+// version is given the book is updated only if it is still at that version.
+func (s Service) Update(c *gofr.Context, id int, book *models.Book, version int) (models.Book, error) {
 	if id <= 0 {
-		return models.Book{}, errors.Error("Invalid Id")
+		return models.Book{}, errInvalidID
 	}
 
-	// missing book fields
-	if isBookFieldsMissing(book) {
-		return models.Book{}, errors.Error("missing book fields")
+	if err := s.validateBook(c, book); err != nil {
+		return models.Book{}, err
 	}
 
-	if !isValidPublishedDate(book.PublishedDate) {
-		return models.Book{}, errors.Error("invalid publishedDate")
+	book.ISBN = normalizeISBN(book.ISBN)
+
+	var bk models.Book
+
+	// the book and its author are checked and the book updated in one transaction, as in Post
+	err := s.unitOfWork.Do(c, func(tx datastore.Tx) error {
+		if err := checkBook(c, tx.Book(), id); err != nil {
+			return err
+		}
+
+		auth, err := lockAuthor(c, tx.Author(), book.AuthorID)
+		if err != nil {
+			return err
+		}
+
+		if err := s.checkDuplicate(c, tx.Book(), id, book); err != nil {
+			return err
+		}
+
+		// the datastore returns the stored book, which carries the new author
+		bk, err = tx.Book().Update(c, id, book, version)
+		bk.Auth = auth
+
+		return versionError(err, id, version)
+	})
+	if err != nil {
+		return models.Book{}, err
 	}
 
-	if !isValidPublication(book.Publication) {
-		return models.Book{}, errors.Error("invalid publication")
+	return bk, nil
+}
+
+// Patch method is to update only the given Book details, the given fields follow the rules of Book and the stored book is returned along with author details.
+// A given version is honoured as in Update.
+func (s Service) Patch(c *gofr.Context, id int, patch models.BookPatch, version int) (models.Book, error) {
+	if id <= 0 {
+		return models.Book{}, errInvalidID
 	}
 
-	check := s.datastoreBook.IsBookPresent(c, id)
-	if check {
-		return models.Book{}, errors.EntityNotFound{Entity: "Book", ID: strconv.Itoa(id)}
+	if err := s.validateBook(c, patch); err != nil {
+		return models.Book{}, err
 	}
 
-	var err error
-	book.Auth, err = s.datastoreAuthor.IncludeAuthor(c, book.AuthorID)
+	if patch.ISBN != nil {
+		isbn := normalizeISBN(*patch.ISBN)
+		patch.ISBN = &isbn
+	}
+
+	var book models.Book
+
+	// the book and a new author are checked and the book patched in one transaction, as in Post
+	err := s.unitOfWork.Do(c, func(tx datastore.Tx) error {
+		if err := checkBook(c, tx.Book(), id); err != nil {
+			return err
+		}
+
+		if patch.AuthorID != nil {
+			if _, err := lockAuthor(c, tx.Author(), *patch.AuthorID); err != nil {
+				return err
+			}
+		}
+
+		if patch.Title != nil || patch.AuthorID != nil || patch.Publication != nil || patch.ISBN != nil {
+			stored, err := tx.Book().GetByID(c, id)
+			if err != nil {
+				return versionError(err, id, 0)
+			}
+
+			patched := applyPatch(stored, patch)
+
+			if err := s.checkDuplicate(c, tx.Book(), id, &patched); err != nil {
+				return err
+			}
+		}
+
+		var err error
+
+		book, err = tx.Book().Patch(c, id, patch, version)
+
+		return versionError(err, id, version)
+	})
 	if err != nil {
-		return models.Book{}, errors.EntityNotFound{Entity: "Author", ID: strconv.Itoa(id)}
+		return models.Book{}, err
 	}
 
-	bk, err2 := s.datastoreBook.Update(c, id, book)
-	if err2 != nil {
-		return models.Book{}, err2
+	books := []models.Book{book}
+
+	if err := s.includeAuthors(c, books); err != nil {
+		return models.Book{}, err
 	}
 
-	return bk, nil
+	return books[0], nil
 }
 
-// Delete method is to delete Book details
-func (s Service) Delete(c *gofr.Context, id int) (int, error) {
+// Delete method is to delete Book details, when a version is given only if the book is still at it
+func (s Service) Delete(c *gofr.Context, id, version int) (int, error) {
 	// Checking invalid id
 	if id <= 0 {
-		return 0, errors.Error("invalid id")
+		return 0, errInvalidID
 	}
 
-	check := s.datastoreBook.IsBookPresent(c, id)
-
-	if check {
-		return 0, errors.EntityNotFound{Entity: "Book", ID: strconv.Itoa(id)}
+	if err := checkBook(c, s.datastoreBook, id); err != nil {
+		return 0, err
 	}
 
-	rowAffected, err := s.datastoreBook.Delete(c, id)
+	rowAffected, err := s.datastoreBook.Delete(c, id, version)
 	if err != nil {
 		return 0, err
 	}
 
+	if rowAffected == 0 {
+		return 0, versionError(sql.ErrNoRows, id, version)
+	}
+
 	return rowAffected, nil
 }
 
-// GetAll method is to get the details of book according to title and author details
-func (s Service) GetAll(c *gofr.Context, title, includeAuthor string) ([]models.Book, error) {
-	// To store book details
-	var books []models.Book
+// GetAll method is to get a sorted page of books matching the filter along with author details
+func (s Service) GetAll(c *gofr.Context, filter models.BookFilter, includeAuthor string, sort []models.SortField,
+	page models.Page) ([]models.Book, models.PageMeta, error) {
+	if err := validateFilter(filter); err != nil {
+		return []models.Book{}, models.PageMeta{}, err
+	}
+
+	sort, err := normalizeSort(sort)
+	if err != nil {
+		return []models.Book{}, models.PageMeta{}, err
+	}
+
+	page, err = normalizePage(page, len(sort))
+	if err != nil {
+		return []models.Book{}, models.PageMeta{}, err
+	}
+
+	// Fetching one extra book to know whether there is a next page
+	fetch := page
+	fetch.Limit++
+
+	books, err := s.datastoreBook.GetAll(c, filter, sort, fetch)
+	if err != nil {
+		return []models.Book{}, models.PageMeta{}, err
+	}
 
-	var err error
+	var meta models.PageMeta
 
-	if title != "" {
-		books, err = s.datastoreBook.GetBookByTitle(c, title)
-	} else {
-		books, err = s.datastoreBook.GetAll(c)
+	if len(books) > page.Limit {
+		books = books[:page.Limit]
+		meta.NextCursor = encodeCursor(books[len(books)-1], sort)
 	}
 
+	meta.Total, err = s.datastoreBook.Count(c, filter)
 	if err != nil {
-		return []models.Book{}, err
+		return []models.Book{}, models.PageMeta{}, err
 	}
 
 	if includeAuthor == "true" {
-		for i := range books {
-			author, err := s.datastoreAuthor.IncludeAuthor(c, books[i].AuthorID)
-			if err != nil {
-				return []models.Book{}, err
-			}
+		if err := s.includeAuthors(c, books); err != nil {
+			return []models.Book{}, models.PageMeta{}, err
+		}
+	}
+
+	return books, meta, nil
+}
 
-			books[i].Auth = author
+// GetByAuthorID method is to get a page of the Books written by an author
+func (s Service) GetByAuthorID(c *gofr.Context, authorID int, page models.Page) ([]models.Book, models.PageMeta, error) {
+	// Checking invalid id
+	if authorID <= 0 {
+		return []models.Book{}, models.PageMeta{}, errInvalidID
+	}
+
+	if err := s.checkAuthor(c, authorID); err != nil {
+		return []models.Book{}, models.PageMeta{}, err
+	}
+
+	return s.GetAll(c, models.BookFilter{AuthorID: authorID}, "", nil, page)
+}
+
+// includeAuthors is to embed the author details in the books fetching all the authors at once
+func (s Service) includeAuthors(c *gofr.Context, books []models.Book) error {
+	if len(books) == 0 {
+		return nil
+	}
+
+	ids := make([]int, 0, len(books))
+	seen := make(map[int]bool, len(books))
+
+	for i := range books {
+		if !seen[books[i].AuthorID] {
+			seen[books[i].AuthorID] = true
+
+			ids = append(ids, books[i].AuthorID)
 		}
 	}
 
-	return books, nil
+	authors, err := s.datastoreAuthor.IncludeAuthors(c, ids)
+	if err != nil {
+		return err
+	}
+
+	for i := range books {
+		books[i].Auth = authors[books[i].AuthorID]
+	}
+
+	return nil
+}
+
+// validateFilter is to validate the book listing filter
+func validateFilter(filter models.BookFilter) error {
+	switch filter.TitleMatch {
+	case "", models.MatchExact, models.MatchPrefix, models.MatchContains:
+	default:
+		return errors.InvalidParam{Code: errors.CodeInvalidParam, Params: []string{"titleMatch"}}
+	}
+
+	if filter.AuthorID < 0 {
+		return errors.InvalidParam{Code: errors.CodeInvalidParam, Params: []string{"authorId"}}
+	}
+
+	return nil
+}
+
+// normalizeSort is to reject the repeated sort fields and add bookId to break the ties,
+// the unknown fields are rejected by the datastore which owns the sortable columns
+func normalizeSort(sort []models.SortField) ([]models.SortField, error) {
+	seen := make(map[string]bool, len(sort))
+
+	for _, s := range sort {
+		if seen[s.Field] {
+			return nil, errors.InvalidParam{Code: errors.CodeInvalidParam, Params: []string{"sort"}}
+		}
+
+		seen[s.Field] = true
+	}
+
+	// bookId is unique, so ordering by it last makes the order deterministic
+	if !seen["bookId"] {
+		sort = append(sort[:len(sort):len(sort)], models.SortField{Field: "bookId"})
+	}
+
+	return sort, nil
+}
+
+// normalizePage is to validate the pagination params and fill in the defaults
+func normalizePage(page models.Page, sortKeys int) (models.Page, error) {
+	switch {
+	case page.Limit < 0 || page.Limit > maxLimit:
+		return models.Page{}, errors.InvalidParam{Code: errors.CodeInvalidParam, Params: []string{"limit"}}
+	case page.Offset < 0:
+		return models.Page{}, errors.InvalidParam{Code: errors.CodeInvalidParam, Params: []string{"offset"}}
+	case page.Cursor != "" && page.Offset != 0:
+		return models.Page{}, errors.InvalidParam{Code: errors.CodeInvalidParam, Params: []string{"offset", "cursor"}}
+	}
+
+	if page.Limit == 0 {
+		page.Limit = defaultLimit
+	}
+
+	if page.Cursor != "" {
+		after, err := decodeCursor(page.Cursor)
+		if err != nil || len(after) != sortKeys {
+			return models.Page{}, errors.InvalidParam{Code: errors.CodeInvalidParam, Params: []string{"cursor"}}
+		}
+
+		page.After = after
+	}
+
+	return page, nil
+}
+
+// encodeCursor is to build the opaque cursor holding the sort key values of the given book
+func encodeCursor(book models.Book, sort []models.SortField) string {
+	values := make([]string, len(sort))
+
+	for i, s := range sort {
+		values[i] = sortValue(book, s.Field)
+	}
+
+	b, _ := json.Marshal(values)
+
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+// decodeCursor is to read the sort key values back from an opaque cursor
+func decodeCursor(cursor string) ([]string, error) {
+	b, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return nil, err
+	}
+
+	var values []string
+
+	if err := json.Unmarshal(b, &values); err != nil {
+		return nil, err
+	}
+
+	return values, nil
+}
+
+// sortValue is to get the value of a sortable field of the book
+func sortValue(book models.Book, field string) string {
+	switch field {
+	case "title":
+		return book.Title
+	case "authorId":
+		return strconv.Itoa(book.AuthorID)
+	case "publication":
+		return book.Publication
+	case "publishedDate":
+		return book.PublishedDate.String()
+	default:
+		return strconv.Itoa(book.BookID)
+	}
+}
+
+// checkBook is to make sure that the book is stored, a failure of the DB is returned as it is
+func checkBook(c *gofr.Context, books datastore.Book, id int) error {
+	exists, err := books.Exists(c, id)
+	if err != nil {
+		return err
+	}
+
+	if !exists {
+		return errors.NotFound{Entity: "Book", ID: strconv.Itoa(id)}
+	}
+
+	return nil
+}
+
+// versionError is to tell the sql.ErrNoRows of a datastore change from any other failure, the book is no
+// longer at the given version or, without one, it was deleted after it was checked
+func versionError(err error, id, version int) error {
+	switch {
+	case err != sql.ErrNoRows:
+		return err
+	case version > 0:
+		return errors.PreconditionFailed{Entity: "Book", ID: strconv.Itoa(id)}
+	default:
+		return errors.NotFound{Entity: "Book", ID: strconv.Itoa(id)}
+	}
+}
+
+// checkAuthor is to make sure that the author is stored, a failure of the DB is returned as it is
+func (s Service) checkAuthor(c *gofr.Context, id int) error {
+	exists, err := s.datastoreAuthor.Exists(c, id)
+	if err != nil {
+		return err
+	}
+
+	if !exists {
+		return errors.NotFound{Entity: "Author", ID: strconv.Itoa(id)}
+	}
+
+	return nil
+}
+
+// lockAuthor is to get the author of a book locking it until the end of the transaction of the datastore,
+// NotFound is returned when it does not exist and a failure of the DB as it is
+func lockAuthor(c *gofr.Context, authors datastore.Author, id int) (models.Author, error) {
+	author, err := authors.Lock(c, id)
+
+	switch {
+	case err == sql.ErrNoRows:
+		return models.Author{}, errors.NotFound{Entity: "Author", ID: strconv.Itoa(id)}
+	case err != nil:
+		return models.Author{}, err
+	}
+
+	return author, nil
+}
+
+// checkDuplicate is to make sure that the book is not a duplicate of a stored one other than the one with the
+// given id, 0 for a new book. A book is told apart by its ISBN and, as per the duplicateRule, by its title,
+// author and publication either only when it has no ISBN as the uq_book_title index does or whatever its ISBN.
+func (s Service) checkDuplicate(c *gofr.Context, books datastore.Book, id int, book *models.Book) error {
+	if err := checkISBN(c, books, id, book.ISBN); err != nil {
+		return err
+	}
+
+	anyISBN := s.duplicateRule == models.DuplicateTitle
+	if book.ISBN != "" && !anyISBN {
+		return nil
+	}
+
+	stored, err := books.GetDuplicate(c, id, book, anyISBN)
+
+	switch {
+	case err == sql.ErrNoRows:
+		return nil
+	case err != nil:
+		return err
+	}
+
+	return bookExists(stored)
+}
+
+// applyPatch is to get the book the patch makes of the stored one
+func applyPatch(book models.Book, patch models.BookPatch) models.Book {
+	if patch.AuthorID != nil {
+		book.AuthorID = *patch.AuthorID
+	}
+
+	if patch.Title != nil {
+		book.Title = *patch.Title
+	}
+
+	if patch.Publication != nil {
+		book.Publication = *patch.Publication
+	}
+
+	if patch.PublishedDate != nil {
+		book.PublishedDate = *patch.PublishedDate
+	}
+
+	if patch.ISBN != nil {
+		book.ISBN = *patch.ISBN
+	}
+
+	return book
 }
 
-func isValidPublishedDate(date string) bool {
-	p := strings.Split(date, "/")
-	day, _ := strconv.Atoi(p[0])
-	month, _ := strconv.Atoi(p[1])
-	year, _ := strconv.Atoi(p[2])
+// checkISBN is to make sure that the ISBN is not taken by a book other than the one with the given id
+func checkISBN(c *gofr.Context, books datastore.Book, id int, isbn models.ISBN) error {
+	if isbn == "" {
+		return nil
+	}
+
+	stored, err := books.GetByISBN(c, isbn)
 
 	switch {
-	case day < 0 || day > 31:
-		return false
-	case month < 0 || month > 12:
-		return false
-	case year > 2022 || year < 1880:
-		return false
+	case err == sql.ErrNoRows:
+		return nil
+	case err != nil:
+		return err
+	case stored.BookID != id:
+		return bookExists(stored)
 	}
 
-	return true
+	return nil
 }
 
-func isValidPublication(publication string) bool {
-	pub := strings.ToLower(publication)
+// bookExists is the conflict of a book clashing with a stored one, it points to the stored book
+func bookExists(stored models.Book) error {
+	return errors.Conflict{Code: errors.CodeBookExists,
+		Reason:     fmt.Sprintf("book %v already exists at /book/%v", stored.Title, stored.BookID),
+		ResourceID: strconv.Itoa(stored.BookID)}
+}
 
-	if pub == "scholastic" || pub == "arihant" || pub == "penguin" {
-		return true
+// normalizeISBN is to get the ISBN-13 of a validated ISBN, an empty one is kept empty
+func normalizeISBN(isbn models.ISBN) models.ISBN {
+	normalized, err := models.ParseISBN(string(isbn))
+	if err != nil {
+		return ""
 	}
 
-	return false
+	return normalized
+}
+
+// isValidPublishedDate is to check that the book was published after firstPublishedYear, and no later than
+// the horizon after today
+func (s Service) isValidPublishedDate(date models.Date) bool {
+	now := s.now().UTC()
+	last := models.NewDate(now.Year(), now.Month(), now.Day()+s.horizonDays)
+
+	return date.Time().Year() >= firstPublishedYear && !date.Time().After(last.Time())
 }
 
-func isBookFieldsMissing(book *models.Book) bool {
-	if book.PublishedDate == "" || book.Publication == "" || book.Title == "" {
-		return true
+// validateBook is to check a Book or BookPatch payload, reporting every offending field. The publication
+// must be registered as a publisher, whatever its case.
+func (s Service) validateBook(c *gofr.Context, payload interface{}) error {
+	// the error of looking up the publisher, which is not a fault of the payload
+	var lookupErr error
+
+	checks := map[string]validate.Check{
+		"publication": func(publication string) string {
+			_, err := s.datastorePublisher.GetByName(c, publication)
+
+			switch {
+			case err == sql.ErrNoRows:
+				return errors.ReasonUnknownPublication
+			case err != nil:
+				lookupErr = err
+			}
+
+			return ""
+		},
+		"published": func(value string) string {
+			if date, err := models.ParseDate(value); err == nil && !s.isValidPublishedDate(date) {
+				return errors.ReasonOutOfRange
+			}
+
+			return ""
+		},
+	}
+
+	fields := validate.Struct(payload, checks)
+
+	if lookupErr != nil {
+		return lookupErr
+	}
+
+	if len(fields) > 0 {
+		return errors.Validation{Code: errors.CodeInvalidField, Fields: fields, Reason: "invalid book"}
 	}
 
-	return false
+	return nil
 }
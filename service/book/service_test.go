@@ -1,22 +1,144 @@
 package book
 
 import (
-	"developer.zopsmart.com/go/gofr/pkg/errors"
+	"database/sql"
+	gofrErrors "developer.zopsmart.com/go/gofr/pkg/errors"
 	"developer.zopsmart.com/go/gofr/pkg/gofr"
 	"log"
 	"reflect"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/golang/mock/gomock"
 
 	"mytest/datastore"
 	"mytest/models"
+	"mytest/models/errors"
 )
 
-var author = models.Author{AuthID: 1, FirstName: "Chetan", LastName: "Bhagat", Dob: "06/04/2001", PenName: "Chetan"}
+var author = models.Author{AuthID: 1, FirstName: "Chetan", LastName: "Bhagat", Dob: models.NewDate(2001, 4, 6),
+	PenName: "Chetan"}
+
+// clock is the fixed clock of the tests, in the afternoon of 10 May 2024
+func clock() time.Time {
+	return time.Date(2024, 5, 10, 15, 0, 0, 0, time.UTC)
+}
+
+// publishers is the registry the publication of the books of the tests is checked against
+var publishers = map[string]models.Publisher{
+	"scholastic": {PublisherID: 1, Name: "Scholastic"},
+	"arihant":    {PublisherID: 2, Name: "Arihant"},
+	"penguin":    {PublisherID: 3, Name: "Penguin"},
+}
+
+// newMockPublisher is to get a publisher datastore looking the names up in publishers, ignoring the case
+func newMockPublisher(ctr *gomock.Controller) *datastore.MockPublisher {
+	mockPublisher := datastore.NewMockPublisher(ctr)
+
+	mockPublisher.EXPECT().GetByName(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(c *gofr.Context, name string) (models.Publisher, error) {
+			publisher, ok := publishers[strings.ToLower(name)]
+			if !ok {
+				return models.Publisher{}, sql.ErrNoRows
+			}
+
+			return publisher, nil
+		}).AnyTimes()
+
+	return mockPublisher
+}
+
+// newMockUnitOfWork is to get a unit of work running the work on the given datastores
+func newMockUnitOfWork(ctr *gomock.Controller, book datastore.Book, author datastore.Author) *datastore.MockUnitOfWork {
+	tx := datastore.NewMockTx(ctr)
+	tx.EXPECT().Book().Return(book).AnyTimes()
+	tx.EXPECT().Author().Return(author).AnyTimes()
+
+	unitOfWork := datastore.NewMockUnitOfWork(ctr)
+	unitOfWork.EXPECT().Do(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(c *gofr.Context, work func(tx datastore.Tx) error) error {
+			return work(tx)
+		}).AnyTimes()
+
+	return unitOfWork
+}
+
+// invalidBook is the validation error of a book payload with the given offending fields
+func invalidBook(fields ...errors.FieldError) error {
+	return errors.Validation{Code: errors.CodeInvalidField, Fields: fields, Reason: "invalid book"}
+}
+
+// TestValidateBook function is to test that every offending field of a book is reported
+func TestValidateBook(t *testing.T) {
+	badDate, _ := models.ParseDate("31/02/2016")
+
+	testcases := []struct {
+		desc        string
+		book        models.Book
+		horizonDays int
+		lookupErr   error
+		err         error
+	}{
+		{desc: "valid", book: models.Book{AuthorID: 1, Title: "2 States", Publication: "penguin",
+			PublishedDate: models.NewDate(2016, 3, 16)}},
+		{desc: "publication of another case", book: models.Book{AuthorID: 1, Title: "2 States", Publication: "PENGUIN",
+			PublishedDate: models.NewDate(2016, 3, 16)}},
+		{desc: "error in publisher lookup", book: models.Book{AuthorID: 1, Title: "2 States", Publication: "Penguin",
+			PublishedDate: models.NewDate(2016, 3, 16)}, lookupErr: gofrErrors.Error("error in GetByName"),
+			err: gofrErrors.Error("error in GetByName")},
+		{desc: "missing fields", book: models.Book{}, err: invalidBook(
+			errors.FieldError{Field: "authID", Reason: errors.ReasonMissing},
+			errors.FieldError{Field: "title", Reason: errors.ReasonMissing},
+			errors.FieldError{Field: "publication", Reason: errors.ReasonMissing},
+			errors.FieldError{Field: "publishedDate", Reason: errors.ReasonMissing})},
+		{desc: "invalid fields", book: models.Book{AuthorID: 1, Title: strings.Repeat("a", 101), Publication: "Lenin",
+			PublishedDate: badDate}, err: invalidBook(
+			errors.FieldError{Field: "title", Reason: errors.ReasonTooLong},
+			errors.FieldError{Field: "publication", Reason: errors.ReasonUnknownPublication},
+			errors.FieldError{Field: "publishedDate", Reason: errors.ReasonBadFormat})},
+		{desc: "published date out of range", book: models.Book{AuthorID: 1, Title: "2 States", Publication: "Arihant",
+			PublishedDate: models.NewDate(1850, 3, 16)}, err: invalidBook(
+			errors.FieldError{Field: "publishedDate", Reason: errors.ReasonOutOfRange})},
+		{desc: "published today", book: models.Book{AuthorID: 1, Title: "2 States", Publication: "Arihant",
+			PublishedDate: models.NewDate(2024, 5, 10)}},
+		{desc: "published tomorrow", book: models.Book{AuthorID: 1, Title: "2 States", Publication: "Arihant",
+			PublishedDate: models.NewDate(2024, 5, 11)}, err: invalidBook(
+			errors.FieldError{Field: "publishedDate", Reason: errors.ReasonOutOfRange})},
+		{desc: "announced within the horizon", book: models.Book{AuthorID: 1, Title: "2 States", Publication: "Arihant",
+			PublishedDate: models.NewDate(2024, 6, 9)}, horizonDays: 30},
+		{desc: "announced after the horizon", book: models.Book{AuthorID: 1, Title: "2 States", Publication: "Arihant",
+			PublishedDate: models.NewDate(2024, 6, 10)}, horizonDays: 30, err: invalidBook(
+			errors.FieldError{Field: "publishedDate", Reason: errors.ReasonOutOfRange})},
+	}
+
+	for i, v := range testcases {
+		var c *gofr.Context
+
+		ctr := gomock.NewController(t)
+		mockPublisher := newMockPublisher(ctr)
+
+		if v.lookupErr != nil {
+			mockPublisher = datastore.NewMockPublisher(ctr)
+			mockPublisher.EXPECT().GetByName(c, v.book.Publication).Return(models.Publisher{}, v.lookupErr)
+		}
+
+		service := New(datastore.NewMockBook(ctr), datastore.NewMockAuthor(ctr), mockPublisher,
+			datastore.NewMockUnitOfWork(ctr), clock, v.horizonDays, models.DuplicateISBN)
+
+		err := service.validateBook(c, &v.book)
+
+		if !reflect.DeepEqual(err, v.err) {
+			t.Errorf("desc : %v ,[TEST%d]Failed. Got %v\tExpected %v\n", v.desc, i+1, err, v.err)
+		}
+	}
+}
 
 // TestBook_Post function is to test post author details
 func TestBook_Post(t *testing.T) {
+	badMonth, _ := models.ParseDate("16/33/2016")
+	badDay, _ := models.ParseDate("116/03/2011")
+
 	testcases := []struct {
 		desc             string
 		req              models.Book
@@ -24,51 +146,64 @@ func TestBook_Post(t *testing.T) {
 		includeAuthorErr error
 		PostErr          error
 	}{
-		{desc: "valid details", req: models.Book{BookID: 1, AuthorID: 1,
-			Auth:  models.Author{AuthID: 1, FirstName: "Chetan", LastName: "Bhagat", Dob: "06/04/2001", PenName: "Chetan"},
-			Title: "2 States", Publication: "Scholastic", PublishedDate: "16/03/2016"},
+		{desc: "valid details", req: models.Book{AuthorID: 1,
+			Auth: models.Author{AuthID: 1, FirstName: "Chetan", LastName: "Bhagat", Dob: models.NewDate(2001, 4, 6),
+				PenName: "Chetan"},
+			Title: "2 States", Publication: "Scholastic", PublishedDate: models.NewDate(2016, 3, 16)},
 			response: models.Book{BookID: 1, AuthorID: 1,
-				Auth:  models.Author{AuthID: 1, FirstName: "Chetan", LastName: "Bhagat", Dob: "06/04/2001", PenName: "Chetan"},
-				Title: "2 States", Publication: "Scholastic", PublishedDate: "16/03/2016"}, includeAuthorErr: nil, PostErr: nil},
-		{desc: "invalid id", req: models.Book{BookID: -11, AuthorID: 1,
-			Auth:  models.Author{AuthID: 1, FirstName: "Chetan", LastName: "Bhagat", Dob: "06/04/2001", PenName: "Chetan"},
-			Title: "2 States", Publication: "Scholastic", PublishedDate: "16/03/2016"}, response: models.Book{}, includeAuthorErr: nil, PostErr: nil},
+				Auth: models.Author{AuthID: 1, FirstName: "Chetan", LastName: "Bhagat",
+					Dob: models.NewDate(2001, 4, 6), PenName: "Chetan"},
+				Title: "2 States", Publication: "Scholastic", PublishedDate: models.NewDate(2016, 3, 16)},
+			includeAuthorErr: nil, PostErr: nil},
 		{desc: "invalid publication", req: models.Book{BookID: 1, AuthorID: 1,
-			Auth:  models.Author{AuthID: 1, FirstName: "Chetan", LastName: "Bhagat", Dob: "06/04/2001", PenName: "Chetan"},
-			Title: "2 States", Publication: "Lenin", PublishedDate: "16/03/2016"}, response: models.Book{}, includeAuthorErr: nil, PostErr: nil},
+			Auth: models.Author{AuthID: 1, FirstName: "Chetan", LastName: "Bhagat", Dob: models.NewDate(2001, 4, 6),
+				PenName: "Chetan"},
+			Title: "2 States", Publication: "Lenin", PublishedDate: models.NewDate(2016, 3, 16)},
+			response: models.Book{}, includeAuthorErr: nil, PostErr: nil},
 		{desc: "invalid publishedDate : year", req: models.Book{BookID: 2, AuthorID: 1,
-			Auth:  models.Author{AuthID: 1, FirstName: "Chetan", LastName: "Bhagat", Dob: "06/04/2001", PenName: "Chetan"},
-			Title: "2 States", Publication: "Scholastic", PublishedDate: "16/03/2061"}},
+			Auth: models.Author{AuthID: 1, FirstName: "Chetan", LastName: "Bhagat", Dob: models.NewDate(2001, 4, 6),
+				PenName: "Chetan"},
+			Title: "2 States", Publication: "Scholastic", PublishedDate: models.NewDate(2061, 3, 16)}},
 		{desc: "invalid publishedDate : month", req: models.Book{BookID: 7, AuthorID: 1,
-			Auth:  models.Author{AuthID: 1, FirstName: "Chetan", LastName: "Bhagat", Dob: "06/04/2001", PenName: "Chetan"},
-			Title: "2 States", Publication: "Scholastic", PublishedDate: "16/33/2016"}},
+			Auth: models.Author{AuthID: 1, FirstName: "Chetan", LastName: "Bhagat", Dob: models.NewDate(2001, 4, 6),
+				PenName: "Chetan"},
+			Title: "2 States", Publication: "Scholastic", PublishedDate: badMonth}},
 		{desc: "invalid publishedDate : day", req: models.Book{BookID: 8, AuthorID: 1,
-			Auth:  models.Author{AuthID: 1, FirstName: "Chetan", LastName: "Bhagat", Dob: "06/04/2001", PenName: "Chetan"},
-			Title: "2 States", Publication: "Scholastic", PublishedDate: "116/03/2011"}},
+			Auth: models.Author{AuthID: 1, FirstName: "Chetan", LastName: "Bhagat", Dob: models.NewDate(2001, 4, 6),
+				PenName: "Chetan"},
+			Title: "2 States", Publication: "Scholastic", PublishedDate: badDay}},
 		{desc: "missing title", req: models.Book{BookID: 1, AuthorID: 1,
-			Auth:        models.Author{AuthID: 1, FirstName: "Chetan", LastName: "Bhagat", Dob: "06/04/2001", PenName: "Chetan"},
-			Publication: "Scholastic", PublishedDate: "16/03/2016"}, response: models.Book{}, includeAuthorErr: nil, PostErr: nil},
+			Auth: models.Author{AuthID: 1, FirstName: "Chetan", LastName: "Bhagat", Dob: models.NewDate(2001, 4, 6),
+				PenName: "Chetan"},
+			Publication: "Scholastic", PublishedDate: models.NewDate(2016, 3, 16)}, response: models.Book{},
+			includeAuthorErr: nil, PostErr: nil},
 		{desc: "missing publication", req: models.Book{BookID: 3, AuthorID: 1,
-			Auth:  models.Author{AuthID: 1, FirstName: "Chetan", LastName: "Bhagat", Dob: "06/04/2001", PenName: "Chetan"},
-			Title: "2 States", PublishedDate: "16/03/2016"}, PostErr: errors.Error("missing book fields")},
+			Auth: models.Author{AuthID: 1, FirstName: "Chetan", LastName: "Bhagat", Dob: models.NewDate(2001, 4, 6),
+				PenName: "Chetan"},
+			Title: "2 States", PublishedDate: models.NewDate(2016, 3, 16)}},
 		{desc: "missing publishedDate", req: models.Book{BookID: 4, AuthorID: 1,
-			Auth:  models.Author{AuthID: 1, FirstName: "Chetan", LastName: "Bhagat", Dob: "06/04/2001", PenName: "Chetan"},
+			Auth: models.Author{AuthID: 1, FirstName: "Chetan", LastName: "Bhagat", Dob: models.NewDate(2001, 4, 6),
+				PenName: "Chetan"},
 			Title: "2 States", Publication: "Scholastic"}, response: models.Book{}, includeAuthorErr: nil, PostErr: nil},
 		{desc: "error in includeAuthor", req: models.Book{BookID: 5, AuthorID: 11,
-			Auth:  models.Author{AuthID: 11, FirstName: "Chetan", LastName: "Bhagat", Dob: "06/04/2001", PenName: "Chetan"},
-			Title: "2 States", Publication: "Scholastic", PublishedDate: "16/03/2016"}, response: models.Book{}, PostErr: nil,
-			includeAuthorErr: errors.Error("error in includeAuthor")},
+			Auth: models.Author{AuthID: 11, FirstName: "Chetan", LastName: "Bhagat", Dob: models.NewDate(2001, 4, 6),
+				PenName: "Chetan"},
+			Title: "2 States", Publication: "Scholastic", PublishedDate: models.NewDate(2016, 3, 16)},
+			response: models.Book{}, PostErr: nil,
+			includeAuthorErr: gofrErrors.Error("error in includeAuthor")},
 	}
 
 	ctr := gomock.NewController(t)
 	mockBook := datastore.NewMockBook(ctr)
 	mockAuthor := datastore.NewMockAuthor(ctr)
-	service := New(mockBook, mockAuthor)
+	service := New(mockBook, mockAuthor, newMockPublisher(ctr),
+		newMockUnitOfWork(ctr, mockBook, mockAuthor), clock, 0, models.DuplicateISBN)
 
 	for i, v := range testcases {
 		var c *gofr.Context
 
-		mockAuthor.EXPECT().IncludeAuthor(c, v.req.AuthorID).Return(v.response.Auth, v.includeAuthorErr).AnyTimes()
+		mockAuthor.EXPECT().Lock(c, v.req.AuthorID).Return(v.response.Auth, v.includeAuthorErr).AnyTimes()
+		mockBook.EXPECT().GetDuplicate(c, 0, &v.req, false).Return(models.Book{}, sql.ErrNoRows).AnyTimes()
 		mockBook.EXPECT().Post(c, &v.req).Return(v.response, v.PostErr).AnyTimes()
 
 		resp, err := service.Post(c, &v.req)
@@ -93,20 +228,24 @@ func TestBook_PostErr(t *testing.T) {
 		PostErr          error
 	}{
 		{desc: "error in datastore post", req: models.Book{BookID: 6, AuthorID: 21,
-			Auth:  models.Author{AuthID: 21, FirstName: "Chetan", LastName: "Sharma", Dob: "26/04/2001", PenName: "Sharma"},
-			Title: "3 States", Publication: "Scholastic", PublishedDate: "26/03/2016"}, response: models.Book{},
-			PostErr: errors.Error("error in post"), includeAuthorErr: nil},
+			Auth: models.Author{AuthID: 21, FirstName: "Chetan", LastName: "Sharma", Dob: models.NewDate(2001, 4, 26),
+				PenName: "Sharma"},
+			Title: "3 States", Publication: "Scholastic", PublishedDate: models.NewDate(2016, 3, 26)},
+			response: models.Book{},
+			PostErr:  gofrErrors.Error("error in post"), includeAuthorErr: nil},
 	}
 
 	ctr := gomock.NewController(t)
 	mockBook := datastore.NewMockBook(ctr)
 	mockAuthor := datastore.NewMockAuthor(ctr)
-	service := New(mockBook, mockAuthor)
+	service := New(mockBook, mockAuthor, newMockPublisher(ctr),
+		newMockUnitOfWork(ctr, mockBook, mockAuthor), clock, 0, models.DuplicateISBN)
 
 	for i, v := range testcases {
 		var c *gofr.Context
 
-		mockAuthor.EXPECT().IncludeAuthor(c, v.req.AuthorID).Return(v.response.Auth, v.includeAuthorErr).AnyTimes()
+		mockAuthor.EXPECT().Lock(c, v.req.AuthorID).Return(v.response.Auth, v.includeAuthorErr).AnyTimes()
+		mockBook.EXPECT().GetDuplicate(c, 0, &v.req, false).Return(models.Book{}, sql.ErrNoRows).AnyTimes()
 		mockBook.EXPECT().Post(c, &v.req).Return(v.response, v.PostErr).AnyTimes()
 
 		resp, err := service.Post(c, &v.req)
@@ -121,112 +260,266 @@ func TestBook_PostErr(t *testing.T) {
 	}
 }
 
+// TestBook_PostDuplicate function is to test that a duplicate of a stored book is rejected
+func TestBook_PostDuplicate(t *testing.T) {
+	book := models.Book{AuthorID: 1, Title: "2 States", Publication: "Scholastic",
+		PublishedDate: models.NewDate(2016, 3, 16)}
+	stored := models.Book{BookID: 9, AuthorID: 1, Title: "2 States", Publication: "Scholastic",
+		PublishedDate: models.NewDate(2014, 10, 8)}
+	created := models.Book{BookID: 10, AuthorID: 1, Auth: author, Title: "2 States", Publication: "Scholastic",
+		PublishedDate: models.NewDate(2016, 3, 16)}
+
+	withISBN := stored
+	withISBN.ISBN = "9780804429573"
+	exists := errors.Conflict{Code: errors.CodeBookExists, Reason: "book 2 States already exists at /book/9",
+		ResourceID: "9"}
+
+	testcases := []struct {
+		desc         string
+		rule         string
+		isbn         models.ISBN
+		taken        models.Book
+		takenErr     error
+		lookup       bool
+		duplicate    models.Book
+		duplicateErr error
+		resp         models.Book
+		err          error
+	}{
+		{desc: "no duplicate", rule: models.DuplicateISBN, lookup: true, duplicateErr: sql.ErrNoRows, resp: created},
+		{desc: "duplicate", rule: models.DuplicateISBN, lookup: true, duplicate: stored, err: exists},
+		{desc: "error in lookup", rule: models.DuplicateISBN, lookup: true,
+			duplicateErr: gofrErrors.Error("error in lookup"), err: gofrErrors.Error("error in lookup")},
+		{desc: "isbn-10 told apart by its isbn-13", rule: models.DuplicateISBN, isbn: "0-306-40615-2",
+			takenErr: sql.ErrNoRows, resp: created},
+		{desc: "isbn taken", rule: models.DuplicateISBN, isbn: "978-0-306-40615-7", taken: stored, err: exists},
+		{desc: "title rule without isbn", rule: models.DuplicateTitle, lookup: true, duplicateErr: sql.ErrNoRows,
+			resp: created},
+		{desc: "title rule with isbn", rule: models.DuplicateTitle, isbn: "978-0-306-40615-7", takenErr: sql.ErrNoRows,
+			lookup: true, duplicateErr: sql.ErrNoRows, resp: created},
+		{desc: "title rule duplicate of a book with isbn", rule: models.DuplicateTitle, lookup: true,
+			duplicate: withISBN, err: exists},
+		{desc: "title rule isbn taken", rule: models.DuplicateTitle, isbn: "978-0-306-40615-7", taken: stored,
+			err: exists},
+	}
+
+	for i, v := range testcases {
+		var c *gofr.Context
+
+		req := book
+		req.ISBN = v.isbn
+
+		ctr := gomock.NewController(t)
+		mockBook := datastore.NewMockBook(ctr)
+		mockAuthor := datastore.NewMockAuthor(ctr)
+		service := New(mockBook, mockAuthor, newMockPublisher(ctr),
+			newMockUnitOfWork(ctr, mockBook, mockAuthor), clock, 0, v.rule)
+
+		mockAuthor.EXPECT().Lock(c, req.AuthorID).Return(author, nil)
+
+		if v.isbn != "" {
+			mockBook.EXPECT().GetByISBN(c, models.ISBN("9780306406157")).Return(v.taken, v.takenErr)
+		}
+
+		if v.lookup {
+			mockBook.EXPECT().GetDuplicate(c, 0, &req, v.rule == models.DuplicateTitle).
+				Return(v.duplicate, v.duplicateErr)
+		}
+
+		mockBook.EXPECT().Post(c, &req).Return(created, nil).MaxTimes(1)
+
+		resp, err := service.Post(c, &req)
+
+		if !reflect.DeepEqual(resp, v.resp) {
+			t.Errorf("desc : %v ,[TEST%d]Failed. Got %v\tExpected %v\n", v.desc, i+1, resp, v.resp)
+		}
+
+		if !reflect.DeepEqual(err, v.err) {
+			t.Errorf("desc : %v ,[TEST%d]Failed. Got %v\tExpected %v\n", v.desc, i+1, err, v.err)
+		}
+	}
+}
+
 // TestBook_GetAll function is to test for getting all books
 func TestBook_GetAll(t *testing.T) {
+	book1 := models.Book{BookID: 1, AuthorID: 1, Title: "States", Publication: "Scholastic",
+		PublishedDate: models.NewDate(2016, 3, 16)}
+	book2 := models.Book{BookID: 2, AuthorID: 1, Title: "States", Publication: "Penguin",
+		PublishedDate: models.NewDate(2016, 3, 11)}
+	withAuthor := book1
+	withAuthor.Auth = author
+	withAuthor2 := book2
+	withAuthor2.Auth = author
+
+	byID := []models.SortField{{Field: "bookId"}}
+	byTitle := []models.SortField{{Field: "title", Desc: true}, {Field: "bookId"}}
+
 	testcases := []struct {
 		desc          string
-		title         string
+		filter        models.BookFilter
 		includeAuthor string
+		sort          []models.SortField
+		fetchSort     []models.SortField
+		page          models.Page
+		fetch         models.Page
+		books         []models.Book
+		total         int
 		resp          []models.Book
-		getTitleErr   error
+		meta          models.PageMeta
 		getAuthorErr  error
 		getAllErr     error
+		countErr      error
 	}{
-
-		{
-			"valid details ", "", "false",
-			[]models.Book{{BookID: 1, AuthorID: 1,
-				Auth:  models.Author{AuthID: 1, FirstName: "Chetan", LastName: "Bhagat", Dob: "06/04/2001", PenName: "Chetan"},
-				Title: "States", Publication: "Scholastic", PublishedDate: "16/03/2016"}},
-			nil, nil, nil,
-		},
-		{
-			"get by title", "States", "false",
-			[]models.Book{{BookID: 2, AuthorID: 1, Auth: models.Author{}, Title: "States", Publication: "Scholastic",
-				PublishedDate: "16/03/2016"}},
-			nil,
-			nil,
-			nil,
-		},
-		{
-			"error in get title", "StateOfAmerica", "false",
-			[]models.Book{}, errors.Error("error in get title"),
-			nil, nil,
-		},
-		{
-			"include Author", "Enough", "true",
-			[]models.Book{{BookID: 3, AuthorID: 1, Auth: models.Author{AuthID: 1, FirstName: "Chetan",
-				LastName: "Bhagat", Dob: "06/04/2001", PenName: "Chetan"}, Title: "States", Publication: "Scholastic", PublishedDate: "16/03/2016"}},
-			nil, nil, nil,
-		},
-		{
-			"error in include Author", "Village", "true",
-			[]models.Book{{4, 1, models.Author{1, "shiv",
-				"Bhagat", "06/04/1990", "shiv"}, "nothing", "Scholastic", "16/03/2016"}},
-			nil, errors.Error("error in includeAuthor"), nil,
-		},
+		{desc: "valid details", includeAuthor: "false", fetch: models.Page{Limit: 21}, books: []models.Book{book1},
+			total: 1, resp: []models.Book{book1}, meta: models.PageMeta{Total: 1}},
+		{desc: "get by title", filter: models.BookFilter{Title: "States"}, includeAuthor: "false", fetch: models.Page{Limit: 21},
+			books: []models.Book{book1, book2}, total: 2, resp: []models.Book{book1, book2}, meta: models.PageMeta{Total: 2}},
+		{desc: "next page exists", page: models.Page{Limit: 1}, fetch: models.Page{Limit: 2},
+			books: []models.Book{book1, book2}, total: 2, resp: []models.Book{book1},
+			meta: models.PageMeta{NextCursor: encodeCursor(book1, byID), Total: 2}},
+		{desc: "page after cursor", page: models.Page{Limit: 1, Cursor: encodeCursor(book1, byID)},
+			fetch: models.Page{Limit: 2, Cursor: encodeCursor(book1, byID), After: []string{"1"}}, books: []models.Book{book2},
+			total: 2, resp: []models.Book{book2}, meta: models.PageMeta{Total: 2}},
+		{desc: "sorted by title", sort: []models.SortField{{Field: "title", Desc: true}}, fetchSort: byTitle,
+			page: models.Page{Limit: 1}, fetch: models.Page{Limit: 2}, books: []models.Book{book1, book2}, total: 2,
+			resp: []models.Book{book1}, meta: models.PageMeta{NextCursor: encodeCursor(book1, byTitle), Total: 2}},
+		{desc: "sorted page after cursor", sort: []models.SortField{{Field: "title", Desc: true}}, fetchSort: byTitle,
+			page:  models.Page{Limit: 1, Cursor: encodeCursor(book1, byTitle)},
+			fetch: models.Page{Limit: 2, Cursor: encodeCursor(book1, byTitle), After: []string{"States", "1"}},
+			books: []models.Book{book2}, total: 2, resp: []models.Book{book2}, meta: models.PageMeta{Total: 2}},
+		{desc: "sorted by bookId desc", sort: []models.SortField{{Field: "bookId", Desc: true}},
+			fetchSort: []models.SortField{{Field: "bookId", Desc: true}}, fetch: models.Page{Limit: 21},
+			books: []models.Book{book2, book1}, total: 2, resp: []models.Book{book2, book1}, meta: models.PageMeta{Total: 2}},
+		{desc: "invalid sort field", sort: []models.SortField{{Field: "Auth"}},
+			fetchSort: []models.SortField{{Field: "Auth"}, {Field: "bookId"}}, fetch: models.Page{Limit: 21},
+			resp: []models.Book{}, getAllErr: errors.InvalidParam{Code: errors.CodeInvalidParam, Params: []string{"sort"}}},
+		{desc: "duplicate sort field", sort: []models.SortField{{Field: "title"}, {Field: "title", Desc: true}},
+			resp: []models.Book{}},
+		{desc: "cursor of another sort", sort: []models.SortField{{Field: "title", Desc: true}},
+			page: models.Page{Cursor: encodeCursor(book1, byID)}, resp: []models.Book{}},
+		{desc: "filter by author and dates", filter: models.BookFilter{AuthorID: 1,
+			PublishedAfter:  models.NewDate(2010, 1, 1),
+			PublishedBefore: models.NewDate(2020, 12, 31)}, fetch: models.Page{Limit: 21}, books: []models.Book{book1,
+			book2}, total: 2,
+			resp: []models.Book{book1, book2}, meta: models.PageMeta{Total: 2}},
+		{desc: "error in get all", fetch: models.Page{Limit: 21}, resp: []models.Book{},
+			getAllErr: gofrErrors.Error("error in get all")},
+		{desc: "error in count", fetch: models.Page{Limit: 21}, books: []models.Book{book1}, resp: []models.Book{},
+			countErr: gofrErrors.Error("error in count")},
+		{desc: "include Author", filter: models.BookFilter{Title: "States"}, includeAuthor: "true", fetch: models.Page{Limit: 21},
+			books: []models.Book{book1}, total: 1, resp: []models.Book{withAuthor}, meta: models.PageMeta{Total: 1}},
+		{desc: "include Author once for many books", includeAuthor: "true", fetch: models.Page{Limit: 21},
+			books: []models.Book{book1, book2}, total: 2, resp: []models.Book{withAuthor, withAuthor2},
+			meta: models.PageMeta{Total: 2}},
+		{desc: "include Author of no books", includeAuthor: "true", fetch: models.Page{Limit: 21}, resp: []models.Book{},
+			meta: models.PageMeta{}},
+		{desc: "error in include Author", filter: models.BookFilter{Title: "Village"}, includeAuthor: "true", fetch: models.Page{Limit: 21},
+			books: []models.Book{book1}, total: 1, resp: []models.Book{}, getAuthorErr: gofrErrors.Error("error in includeAuthor")},
+		{desc: "invalid titleMatch", filter: models.BookFilter{Title: "States", TitleMatch: "regex"},
+			resp: []models.Book{}},
+		{desc: "invalid authorId", filter: models.BookFilter{AuthorID: -1}, resp: []models.Book{}},
+		{desc: "invalid limit", page: models.Page{Limit: 101}, resp: []models.Book{}},
+		{desc: "invalid offset", page: models.Page{Offset: -1}, resp: []models.Book{}},
+		{desc: "invalid cursor", page: models.Page{Cursor: "abc"}, resp: []models.Book{}},
+		{desc: "cursor with offset", page: models.Page{Offset: 2, Cursor: encodeCursor(book1, byID)}, resp: []models.Book{}},
 	}
 
-	ctr := gomock.NewController(t)
-	mockBook := datastore.NewMockBook(ctr)
-	mockAuthor := datastore.NewMockAuthor(ctr)
-	service := New(mockBook, mockAuthor)
-
 	for i, v := range testcases {
 		var c *gofr.Context
 
-		mockBook.EXPECT().GetBookByTitle(c, v.title).Return(v.resp, v.getTitleErr).AnyTimes()
-		mockAuthor.EXPECT().IncludeAuthor(c, author.AuthID).Return(author, v.getAuthorErr).AnyTimes()
-		mockBook.EXPECT().GetAll(c).Return(v.resp, v.getAllErr).AnyTimes()
+		ctr := gomock.NewController(t)
+		mockBook := datastore.NewMockBook(ctr)
+		mockAuthor := datastore.NewMockAuthor(ctr)
+		service := New(mockBook, mockAuthor, newMockPublisher(ctr),
+			newMockUnitOfWork(ctr, mockBook, mockAuthor), clock, 0, models.DuplicateISBN)
+
+		books := append([]models.Book{}, v.books...)
+
+		fetchSort := v.fetchSort
+		if fetchSort == nil {
+			fetchSort = byID
+		}
+
+		mockBook.EXPECT().GetAll(c, v.filter, fetchSort, v.fetch).Return(books, v.getAllErr).AnyTimes()
+		mockBook.EXPECT().Count(c, v.filter).Return(v.total, v.countErr).AnyTimes()
+		mockAuthor.EXPECT().IncludeAuthors(c, []int{author.AuthID}).
+			Return(map[int]models.Author{author.AuthID: author}, v.getAuthorErr).MaxTimes(1)
 
-		resp, err := service.GetAll(c, v.title, v.includeAuthor)
+		resp, meta, err := service.GetAll(c, v.filter, v.includeAuthor, v.sort, v.page)
 
 		if !reflect.DeepEqual(resp, v.resp) {
 			t.Errorf("Desc : %v,[TEST%d]Failed. Got %v\tExpected %v\n", v.desc, i+1, resp, v.resp)
 		}
 
+		if !reflect.DeepEqual(meta, v.meta) {
+			t.Errorf("Desc : %v,[TEST%d]Failed. Got %v\tExpected %v\n", v.desc, i+1, meta, v.meta)
+		}
+
 		if err != nil {
 			log.Printf("desc : %v ,[TEST%d]Failed. Got %v", v.desc, i+1, err)
 		}
 	}
 }
 
-// TestBook_GetAllIncludeAuthorErr function is to test for getting all books
-func TestBook_GetAllIncludeAuthorErr(t *testing.T) {
+// TestBook_GetByAuthorID function is to test for get the books of an author
+func TestBook_GetByAuthorID(t *testing.T) {
+	book1 := models.Book{BookID: 1, AuthorID: 1, Title: "States", Publication: "Scholastic",
+		PublishedDate: models.NewDate(2016, 3, 16)}
+	book2 := models.Book{BookID: 2, AuthorID: 1, Title: "Village", Publication: "Penguin",
+		PublishedDate: models.NewDate(2016, 3, 11)}
+	byID := []models.SortField{{Field: "bookId"}}
+
 	testcases := []struct {
-		desc          string
-		title         string
-		includeAuthor string
-		resp          []models.Book
-		getTitleErr   error
-		getAuthorErr  error
-		getAllErr     error
+		desc      string
+		authorID  int
+		page      models.Page
+		fetch     models.Page
+		books     []models.Book
+		total     int
+		resp      []models.Book
+		meta      models.PageMeta
+		checkAuth bool
+		getAllErr error
 	}{
-		{
-			"error in include Author", "Village", "true",
-			[]models.Book{},
-			nil, errors.Error("error in includeAuthor"), nil,
-		},
+		{desc: "valid details", authorID: 1, fetch: models.Page{Limit: 21}, books: []models.Book{book1, book2}, total: 2,
+			resp: []models.Book{book1, book2}, meta: models.PageMeta{Total: 2}},
+		{desc: "next page exists", authorID: 1, page: models.Page{Limit: 1}, fetch: models.Page{Limit: 2},
+			books: []models.Book{book1, book2}, total: 2, resp: []models.Book{book1},
+			meta: models.PageMeta{NextCursor: encodeCursor(book1, byID), Total: 2}},
+		{desc: "no books", authorID: 1, fetch: models.Page{Limit: 21}, resp: []models.Book{}},
+		{desc: "invalid id", authorID: -1, resp: []models.Book{}},
+		{desc: "author not found", authorID: 5, checkAuth: true, resp: []models.Book{}},
+		{desc: "invalid limit", authorID: 1, page: models.Page{Limit: -1}, resp: []models.Book{}},
+		{desc: "error in get all", authorID: 1, fetch: models.Page{Limit: 21}, resp: []models.Book{},
+			getAllErr: gofrErrors.Error("error in get all")},
 	}
 
-	ctr := gomock.NewController(t)
-	mockBook := datastore.NewMockBook(ctr)
-	mockAuthor := datastore.NewMockAuthor(ctr)
-	service := New(mockBook, mockAuthor)
-
 	for i, v := range testcases {
 		var c *gofr.Context
 
-		mockBook.EXPECT().GetBookByTitle(c, v.title).Return(v.resp, v.getTitleErr).AnyTimes()
-		mockAuthor.EXPECT().IncludeAuthor(c, author.AuthID).Return(author, v.getAuthorErr).AnyTimes()
-		mockBook.EXPECT().GetAll(c).Return(v.resp, v.getAllErr).AnyTimes()
+		ctr := gomock.NewController(t)
+		mockBook := datastore.NewMockBook(ctr)
+		mockAuthor := datastore.NewMockAuthor(ctr)
+		service := New(mockBook, mockAuthor, newMockPublisher(ctr),
+			newMockUnitOfWork(ctr, mockBook, mockAuthor), clock, 0, models.DuplicateISBN)
+
+		filter := models.BookFilter{AuthorID: v.authorID}
+		books := append([]models.Book{}, v.books...)
 
-		resp, err := service.GetAll(c, v.title, v.includeAuthor)
+		mockAuthor.EXPECT().Exists(c, v.authorID).Return(!v.checkAuth, nil).AnyTimes()
+		mockBook.EXPECT().GetAll(c, filter, byID, v.fetch).Return(books, v.getAllErr).AnyTimes()
+		mockBook.EXPECT().Count(c, filter).Return(v.total, nil).AnyTimes()
+
+		resp, meta, err := service.GetByAuthorID(c, v.authorID, v.page)
 
 		if !reflect.DeepEqual(resp, v.resp) {
 			t.Errorf("Desc : %v,[TEST%d]Failed. Got %v\tExpected %v\n", v.desc, i+1, resp, v.resp)
 		}
 
+		if !reflect.DeepEqual(meta, v.meta) {
+			t.Errorf("Desc : %v,[TEST%d]Failed. Got %v\tExpected %v\n", v.desc, i+1, meta, v.meta)
+		}
+
 		if err != nil {
 			log.Printf("desc : %v ,[TEST%d]Failed. Got %v", v.desc, i+1, err)
 		}
@@ -235,35 +528,58 @@ func TestBook_GetAllIncludeAuthorErr(t *testing.T) {
 
 // TestBook_GetByID function is to test for get a book
 func TestBook_GetByID(t *testing.T) {
+	withAuthor := models.Book{BookID: 2, AuthorID: 1, Auth: author, Title: "States", Publication: "Scholastic",
+		PublishedDate: models.NewDate(2016, 3, 16)}
+
 	testcases := []struct {
-		desc      string
-		id        int
-		resp      models.Book
-		checkBook bool
-		isBookErr error
-		getIdErr  error
+		desc          string
+		id            int
+		includeAuthor string
+		book          models.Book
+		resp          models.Book
+		checkBook     bool
+		isBookErr     error
+		getIdErr      error
+		getAuthorErr  error
 	}{
 
 		{desc: "valid detail", id: 1, resp: models.Book{BookID: 1, AuthorID: 1,
-			Auth:  models.Author{AuthID: 1, FirstName: "Chetan", LastName: "Bhagat", Dob: "06/04/2001", PenName: "Chetan"},
-			Title: "States", Publication: "Scholastic", PublishedDate: "16/03/2016"}, checkBook: false, isBookErr: nil, getIdErr: nil},
-		{desc: "invalid id", id: -11, getIdErr: errors.Error("invalid id"), resp: models.Book{}, checkBook: false, isBookErr: nil},
-		{desc: "error in IsBookPresent", id: 15, resp: models.Book{}, isBookErr: errors.Error("error in isBook"), checkBook: true, getIdErr: nil},
-		{desc: "error in datastore get", id: 23, resp: models.Book{}, getIdErr: errors.Error("error in Get"), checkBook: false, isBookErr: nil},
+			Auth: models.Author{AuthID: 1, FirstName: "Chetan", LastName: "Bhagat", Dob: models.NewDate(2001, 4, 6),
+				PenName: "Chetan"},
+			Title: "States", Publication: "Scholastic", PublishedDate: models.NewDate(2016, 3, 16)}, checkBook: false,
+			isBookErr: nil, getIdErr: nil},
+		{desc: "invalid id", id: -11, getIdErr: errInvalidID, resp: models.Book{}, checkBook: false, isBookErr: nil},
+		{desc: "error in Exists", id: 15, resp: models.Book{}, isBookErr: gofrErrors.Error("error in Exists"), checkBook: true, getIdErr: nil},
+		{desc: "error in datastore get", id: 23, resp: models.Book{}, getIdErr: gofrErrors.Error("error in Get"), checkBook: false, isBookErr: nil},
+		{desc: "include author", id: 2, includeAuthor: "true", book: models.Book{BookID: 2, AuthorID: 1, Title: "States",
+			Publication: "Scholastic", PublishedDate: models.NewDate(2016, 3, 16)}, resp: withAuthor},
+		{desc: "error in include author", id: 3, includeAuthor: "true", book: models.Book{BookID: 3, AuthorID: 1,
+			Title: "States", Publication: "Scholastic", PublishedDate: models.NewDate(2016, 3, 16)},
+			resp:         models.Book{},
+			getAuthorErr: gofrErrors.Error("error in includeAuthors")},
 	}
 
 	ctr := gomock.NewController(t)
 	mockBook := datastore.NewMockBook(ctr)
 	mockAuthor := datastore.NewMockAuthor(ctr)
-	service := New(mockBook, mockAuthor)
+	service := New(mockBook, mockAuthor, newMockPublisher(ctr),
+		newMockUnitOfWork(ctr, mockBook, mockAuthor), clock, 0, models.DuplicateISBN)
 
 	for i, v := range testcases {
 		var c *gofr.Context
 
-		mockBook.EXPECT().IsBookPresent(c, v.id).Return(v.checkBook).AnyTimes()
-		mockBook.EXPECT().GetByID(c, v.id).Return(v.resp, v.getIdErr).AnyTimes()
+		book := v.resp
+		if v.includeAuthor == "true" {
+			book = v.book
+
+			mockAuthor.EXPECT().IncludeAuthors(c, []int{author.AuthID}).
+				Return(map[int]models.Author{author.AuthID: author}, v.getAuthorErr)
+		}
 
-		resp, err := service.GetByID(c, v.id)
+		mockBook.EXPECT().Exists(c, v.id).Return(!v.checkBook, v.isBookErr).AnyTimes()
+		mockBook.EXPECT().GetByID(c, v.id).Return(book, v.getIdErr).AnyTimes()
+
+		resp, err := service.GetByID(c, v.id, v.includeAuthor)
 
 		if !reflect.DeepEqual(resp, v.resp) {
 			t.Errorf("Desc : %v,[TEST%d]Failed. Got %v\tExpected %v\n", v.desc, i+1, resp, v.resp)
@@ -275,6 +591,88 @@ func TestBook_GetByID(t *testing.T) {
 	}
 }
 
+// TestBook_ExistsError function is to test that a failure of the DB while checking the book or its author
+// is returned as it is and not as a missing book
+func TestBook_ExistsError(t *testing.T) {
+	dbErr := gofrErrors.Error("connection refused")
+
+	ctr := gomock.NewController(t)
+	mockBook := datastore.NewMockBook(ctr)
+	mockAuthor := datastore.NewMockAuthor(ctr)
+	service := New(mockBook, mockAuthor, newMockPublisher(ctr),
+		newMockUnitOfWork(ctr, mockBook, mockAuthor), clock, 0, models.DuplicateISBN)
+
+	var c *gofr.Context
+
+	mockBook.EXPECT().Exists(c, 1).Return(false, dbErr).Times(2)
+	mockAuthor.EXPECT().Exists(c, 2).Return(false, dbErr)
+
+	if _, err := service.GetByID(c, 1, ""); !reflect.DeepEqual(err, dbErr) {
+		t.Errorf("desc : get by id ,Failed. Got %v\tExpected %v\n", err, dbErr)
+	}
+
+	if _, err := service.Delete(c, 1, 0); !reflect.DeepEqual(err, dbErr) {
+		t.Errorf("desc : delete ,Failed. Got %v\tExpected %v\n", err, dbErr)
+	}
+
+	if _, _, err := service.GetByAuthorID(c, 2, models.Page{}); !reflect.DeepEqual(err, dbErr) {
+		t.Errorf("desc : get by author id ,Failed. Got %v\tExpected %v\n", err, dbErr)
+	}
+}
+
+// TestBook_GetByISBN function is to test getting a book by its ISBN-10 or ISBN-13
+func TestBook_GetByISBN(t *testing.T) {
+	book := models.Book{BookID: 2, AuthorID: 1, Title: "States", Publication: "Scholastic",
+		PublishedDate: models.NewDate(2016, 3, 16), ISBN: "9780306406157"}
+	withAuthor := book
+	withAuthor.Auth = author
+
+	testcases := []struct {
+		desc          string
+		isbn          string
+		includeAuthor string
+		getErr        error
+		resp          models.Book
+		err           error
+	}{
+		{desc: "isbn-13", isbn: "9780306406157", resp: book},
+		{desc: "isbn-10", isbn: "0-306-40615-2", includeAuthor: "true", resp: withAuthor},
+		{desc: "invalid isbn", isbn: "0-306-40615-3",
+			err: errors.InvalidParam{Code: errors.CodeInvalidParam, Params: []string{"isbn"}}},
+		{desc: "not found", isbn: "978-0-306-40615-7", getErr: sql.ErrNoRows,
+			err: errors.NotFound{Entity: "Book", ID: "9780306406157"}},
+		{desc: "error in get", isbn: "9780306406157", getErr: gofrErrors.Error("error in get"),
+			err: gofrErrors.Error("error in get")},
+	}
+
+	for i, v := range testcases {
+		var c *gofr.Context
+
+		ctr := gomock.NewController(t)
+		mockBook := datastore.NewMockBook(ctr)
+		mockAuthor := datastore.NewMockAuthor(ctr)
+		service := New(mockBook, mockAuthor, newMockPublisher(ctr),
+			newMockUnitOfWork(ctr, mockBook, mockAuthor), clock, 0, models.DuplicateISBN)
+
+		mockBook.EXPECT().GetByISBN(c, book.ISBN).Return(book, v.getErr).MaxTimes(1)
+
+		if v.includeAuthor == "true" {
+			mockAuthor.EXPECT().IncludeAuthors(c, []int{author.AuthID}).
+				Return(map[int]models.Author{author.AuthID: author}, nil)
+		}
+
+		resp, err := service.GetByISBN(c, v.isbn, v.includeAuthor)
+
+		if !reflect.DeepEqual(resp, v.resp) {
+			t.Errorf("desc : %v ,[TEST%d]Failed. Got %v\tExpected %v\n", v.desc, i+1, resp, v.resp)
+		}
+
+		if !reflect.DeepEqual(err, v.err) {
+			t.Errorf("desc : %v ,[TEST%d]Failed. Got %v\tExpected %v\n", v.desc, i+1, err, v.err)
+		}
+	}
+}
+
 // TestBook_Put function is to test for updating Book
 func TestBook_Put(t *testing.T) {
 	testcases := []struct {
@@ -290,17 +688,28 @@ func TestBook_Put(t *testing.T) {
 			desc: "valid ",
 			id:   1,
 			req: models.Book{BookID: 1, AuthorID: 1, Auth: models.Author{AuthID: 1, FirstName: "Gaurav", LastName: "Singh",
-				Dob: "07/04/2001", PenName: "Gaurav"}, Title: "300 Days", Publication: "Penguin", PublishedDate: "17/03/2016"},
-			resp: models.Book{BookID: 1, AuthorID: 1, Auth: models.Author{AuthID: 1, FirstName: "Gaurav", LastName: "Singh",
-				Dob: "07/04/2001", PenName: "Gaurav"}, Title: "300 Days", Publication: "Penguin", PublishedDate: "17/03/2016"},
+				Dob: models.NewDate(2001, 4, 7), PenName: "Gaurav"}, Title: "300 Days", Publication: "Penguin",
+				PublishedDate: models.NewDate(2016, 3, 17)},
+			resp: models.Book{BookID: 1, AuthorID: 1, Auth: author, Title: "300 Days", Publication: "Penguin",
+				PublishedDate: models.NewDate(2016, 3, 17)},
 			checkBook:        false,
 			putErr:           nil,
 			includeAuthorErr: nil,
 		},
+		{
+			desc: "change author",
+			id:   2,
+			req: models.Book{AuthorID: 2, Title: "300 Days", Publication: "Penguin",
+				PublishedDate: models.NewDate(2016, 3, 17)},
+			resp: models.Book{BookID: 2, AuthorID: 2, Auth: models.Author{AuthID: 2, FirstName: "Gaurav", LastName: "Singh",
+				Dob: models.NewDate(2001, 4, 7), PenName: "Gaurav"}, Title: "300 Days", Publication: "Penguin",
+				PublishedDate: models.NewDate(2016, 3, 17)},
+		},
 		{
 			desc: "invalid id",
 			req: models.Book{BookID: 3, AuthorID: 1, Auth: models.Author{AuthID: 1, FirstName: "Gaurav", LastName: "Singh",
-				Dob: "07/04/2001", PenName: "Gaurav"}, Title: "300 Days", Publication: "Arihant", PublishedDate: "17/03/2016"},
+				Dob: models.NewDate(2001, 4, 7), PenName: "Gaurav"}, Title: "300 Days", Publication: "Arihant",
+				PublishedDate: models.NewDate(2016, 3, 17)},
 			resp:             models.Book{},
 			id:               -11,
 			checkBook:        false,
@@ -311,7 +720,8 @@ func TestBook_Put(t *testing.T) {
 			desc: "invalid publication",
 			id:   4,
 			req: models.Book{BookID: 4, AuthorID: 1, Auth: models.Author{AuthID: 1, FirstName: "Gaurav", LastName: "Singh",
-				Dob: "07/04/2001", PenName: "Gaurav"}, Title: "300 Days", Publication: "lenin", PublishedDate: "17/03/2016"},
+				Dob: models.NewDate(2001, 4, 7), PenName: "Gaurav"}, Title: "300 Days", Publication: "lenin",
+				PublishedDate: models.NewDate(2016, 3, 17)},
 			resp:             models.Book{},
 			checkBook:        false,
 			putErr:           nil,
@@ -321,7 +731,8 @@ func TestBook_Put(t *testing.T) {
 			desc: "invalid publishedDate",
 			id:   5,
 			req: models.Book{BookID: 5, AuthorID: 1, Auth: models.Author{AuthID: 1, FirstName: "Gaurav", LastName: "Singh",
-				Dob: "07/04/2001", PenName: "Gaurav"}, Title: "300 Days", Publication: "Penguin", PublishedDate: "17/03/2061"},
+				Dob: models.NewDate(2001, 4, 7), PenName: "Gaurav"}, Title: "300 Days", Publication: "Penguin",
+				PublishedDate: models.NewDate(2061, 3, 17)},
 			resp:             models.Book{},
 			checkBook:        false,
 			putErr:           nil,
@@ -331,7 +742,8 @@ func TestBook_Put(t *testing.T) {
 			desc: "missing book fields",
 			id:   6,
 			req: models.Book{BookID: 6, AuthorID: 1, Auth: models.Author{AuthID: 1, FirstName: "Gaurav", LastName: "Singh",
-				Dob: "07/04/2001", PenName: "Gaurav"}, Publication: "lenin", PublishedDate: "17/03/2016"},
+				Dob: models.NewDate(2001, 4, 7), PenName: "Gaurav"}, Publication: "lenin",
+				PublishedDate: models.NewDate(2016, 3, 17)},
 			resp:             models.Book{},
 			checkBook:        false,
 			putErr:           nil,
@@ -341,7 +753,8 @@ func TestBook_Put(t *testing.T) {
 			desc: "error in isBookPresent",
 			id:   7,
 			req: models.Book{BookID: 7, AuthorID: 1, Auth: models.Author{AuthID: 1, FirstName: "Gaurav", LastName: "Singh",
-				Dob: "07/04/2001", PenName: "Gaurav"}, Title: "300 Days", Publication: "Penguin", PublishedDate: "17/03/2016"},
+				Dob: models.NewDate(2001, 4, 7), PenName: "Gaurav"}, Title: "300 Days", Publication: "Penguin",
+				PublishedDate: models.NewDate(2016, 3, 17)},
 			resp:             models.Book{},
 			checkBook:        true,
 			putErr:           nil,
@@ -351,43 +764,146 @@ func TestBook_Put(t *testing.T) {
 			desc: "error in put Author",
 			id:   9,
 			req: models.Book{BookID: 9, AuthorID: 1, Auth: models.Author{AuthID: 1, FirstName: "Gaurav", LastName: "Singh",
-				Dob: "07/04/2001", PenName: "Gaurav"}, Title: "300 Days", Publication: "Penguin", PublishedDate: "17/03/2016"},
+				Dob: models.NewDate(2001, 4, 7), PenName: "Gaurav"}, Title: "300 Days", Publication: "Penguin",
+				PublishedDate: models.NewDate(2016, 3, 17)},
 			resp:             models.Book{},
 			checkBook:        false,
-			putErr:           errors.Error("error in putAuthor"),
+			putErr:           gofrErrors.Error("error in putAuthor"),
 			includeAuthorErr: nil,
 		},
 		{
 			"error in includeAuthor",
 			8,
 			models.Book{BookID: 8, AuthorID: 1, Auth: models.Author{AuthID: 1, FirstName: "Gaurav", LastName: "Chandra",
-				Dob: "07/04/2001", PenName: "Chandra"}, Title: "Days", Publication: "Penguin", PublishedDate: "17/03/2016"},
+				Dob: models.NewDate(2001, 4, 7), PenName: "Chandra"}, Title: "Days", Publication: "Penguin",
+				PublishedDate: models.NewDate(2016, 3, 17)},
 			models.Book{},
 			false,
-			errors.Error("error in include author"),
+			gofrErrors.Error("error in include author"),
 			nil,
 		},
 	}
 
+	for i, v := range testcases {
+		var c *gofr.Context
+
+		ctr := gomock.NewController(t)
+		mockBook := datastore.NewMockBook(ctr)
+		mockAuthor := datastore.NewMockAuthor(ctr)
+		service := New(mockBook, mockAuthor, newMockPublisher(ctr),
+			newMockUnitOfWork(ctr, mockBook, mockAuthor), clock, 0, models.DuplicateISBN)
+
+		// the datastore reads back the stored book, without the author details
+		stored := v.resp
+		stored.Auth = models.Author{}
+
+		mockBook.EXPECT().Exists(c, v.id).Return(!v.checkBook, nil).AnyTimes()
+		mockAuthor.EXPECT().Lock(c, v.req.AuthorID).Return(v.resp.Auth, v.includeAuthorErr).AnyTimes()
+		mockBook.EXPECT().GetDuplicate(c, v.id, &v.req, false).Return(models.Book{}, sql.ErrNoRows).AnyTimes()
+		mockBook.EXPECT().Update(c, v.id, &v.req, 0).Return(stored, v.putErr).AnyTimes()
+
+		resp, err := service.Update(c, v.id, &v.req, 0)
+
+		if !reflect.DeepEqual(resp, v.resp) {
+			t.Errorf("desc : %v ,[TEST%d]Failed. Got %v\tExpected %v\n", v.desc, i+1, resp, v.resp)
+		}
+
+		if err != nil {
+			log.Printf("desc : %v ,[TEST%d]Failed. Got %v", v.desc, i+1, err)
+		}
+	}
+}
+
+// TestBook_Patch function is to test partial update of a book
+func TestBook_Patch(t *testing.T) {
+	title, empty, publication, badPublication := "300 Days", "", "Penguin", "lenin"
+	date := models.NewDate(2016, 3, 17)
+	badDate, _ := models.ParseDate("31/02/2016")
+	authorID, missingAuthor := 1, 9
+	isbn, takenISBN := models.ISBN("9780306406157"), models.ISBN("9780804429573")
+
+	patched := models.Book{BookID: 1, AuthorID: 1, Auth: author, Title: "300 Days", Publication: "Scholastic",
+		PublishedDate: models.NewDate(2016, 3, 16)}
+	duplicate := models.Book{BookID: 3, AuthorID: 1, Title: "300 Days", Publication: "Scholastic",
+		PublishedDate: models.NewDate(2015, 1, 1)}
+
+	testcases := []struct {
+		desc      string
+		id        int
+		patch     models.BookPatch
+		duplicate models.Book
+		resp      models.Book
+		checkBook bool
+		patchErr  error
+		err       error
+	}{
+		{desc: "valid", id: 1, patch: models.BookPatch{Title: &title}, resp: patched},
+		{desc: "all fields", id: 1, patch: models.BookPatch{AuthorID: &authorID, Title: &title, Publication: &publication,
+			PublishedDate: &date}, resp: patched},
+		{desc: "invalid id", id: 0, err: errInvalidID},
+		{desc: "emptied title", id: 2, patch: models.BookPatch{Title: &empty},
+			err: invalidBook(errors.FieldError{Field: "title", Reason: errors.ReasonMissing})},
+		{desc: "invalid publishedDate", id: 3, patch: models.BookPatch{PublishedDate: &badDate},
+			err: invalidBook(errors.FieldError{Field: "publishedDate", Reason: errors.ReasonBadFormat})},
+		{desc: "invalid publication", id: 4, patch: models.BookPatch{Publication: &badPublication},
+			err: invalidBook(errors.FieldError{Field: "publication", Reason: errors.ReasonUnknownPublication})},
+		{desc: "book not found", id: 5, patch: models.BookPatch{Title: &title}, checkBook: true,
+			err: errors.NotFound{Entity: "Book", ID: "5"}},
+		{desc: "author not found", id: 6, patch: models.BookPatch{AuthorID: &missingAuthor},
+			err: errors.NotFound{Entity: "Author", ID: "9"}},
+		{desc: "error in patch", id: 7, patch: models.BookPatch{Title: &title}, patchErr: gofrErrors.Error("error in patch"),
+			err: gofrErrors.Error("error in patch")},
+		{desc: "isbn", id: 1, patch: models.BookPatch{ISBN: &isbn}, resp: patched},
+		{desc: "isbn taken", id: 8, patch: models.BookPatch{ISBN: &takenISBN}, err: errors.Conflict{
+			Code: errors.CodeBookExists, Reason: "book Lolita already exists at /book/4", ResourceID: "4"}},
+		{desc: "duplicate title", id: 9, patch: models.BookPatch{Title: &title}, duplicate: duplicate,
+			err: errors.Conflict{Code: errors.CodeBookExists, Reason: "book 300 Days already exists at /book/3",
+				ResourceID: "3"}},
+		{desc: "duplicate without isbn kept by date", id: 10, patch: models.BookPatch{PublishedDate: &date},
+			duplicate: duplicate, resp: patched},
+	}
+
 	ctr := gomock.NewController(t)
 	mockBook := datastore.NewMockBook(ctr)
 	mockAuthor := datastore.NewMockAuthor(ctr)
-	service := New(mockBook, mockAuthor)
+	service := New(mockBook, mockAuthor, newMockPublisher(ctr),
+		newMockUnitOfWork(ctr, mockBook, mockAuthor), clock, 0, models.DuplicateISBN)
+
+	mockAuthor.EXPECT().Lock(gomock.Any(), authorID).Return(author, nil).AnyTimes()
+	mockAuthor.EXPECT().Lock(gomock.Any(), missingAuthor).Return(models.Author{}, sql.ErrNoRows).AnyTimes()
+	mockAuthor.EXPECT().IncludeAuthors(gomock.Any(), []int{authorID}).Return(map[int]models.Author{authorID: author}, nil).
+		AnyTimes()
+	mockBook.EXPECT().GetByISBN(gomock.Any(), isbn).Return(models.Book{}, sql.ErrNoRows).AnyTimes()
+	mockBook.EXPECT().GetByISBN(gomock.Any(), takenISBN).Return(models.Book{BookID: 4, Title: "Lolita", ISBN: takenISBN}, nil).
+		AnyTimes()
 
 	for i, v := range testcases {
 		var c *gofr.Context
-		mockBook.EXPECT().IsBookPresent(c, v.id).Return(v.checkBook).AnyTimes()
-		mockAuthor.EXPECT().IncludeAuthor(c, author.AuthID).Return(author, v.includeAuthorErr).AnyTimes()
-		mockBook.EXPECT().Update(c, v.id, &v.req).Return(v.resp, v.putErr).AnyTimes()
 
-		resp, err := service.Update(c, v.id, &v.req)
+		// the datastore reads back the stored book, without the author details
+		stored := v.resp
+		stored.Auth = models.Author{}
+
+		duplicateErr := error(nil)
+		if v.duplicate.BookID == 0 {
+			duplicateErr = sql.ErrNoRows
+		}
+
+		// the stored book is read to check the patched one for a duplicate
+		mockBook.EXPECT().GetByID(c, v.id).Return(models.Book{BookID: v.id, AuthorID: 1, Title: "2 States",
+			Publication: "Scholastic", PublishedDate: models.NewDate(2016, 3, 16)}, nil).AnyTimes()
+		mockBook.EXPECT().GetDuplicate(c, v.id, gomock.Any(), false).Return(v.duplicate, duplicateErr).AnyTimes()
+		mockBook.EXPECT().Exists(c, v.id).Return(!v.checkBook, nil).AnyTimes()
+		mockBook.EXPECT().Patch(c, v.id, v.patch, 0).Return(stored, v.patchErr).AnyTimes()
+
+		resp, err := service.Patch(c, v.id, v.patch, 0)
 
 		if !reflect.DeepEqual(resp, v.resp) {
-			t.Errorf("desc : %v ,[TEST%d]Failed. Got %v\tExpected %v\n", v.desc, i+1, resp, v.resp)
+			t.Errorf("Desc : %v,[TEST%d]Failed. Got %v\tExpected %v\n", v.desc, i+1, resp, v.resp)
 		}
 
-		if err != nil {
-			log.Printf("desc : %v ,[TEST%d]Failed. Got %v", v.desc, i+1, err)
+		if !reflect.DeepEqual(err, v.err) {
+			t.Errorf("Desc : %v,[TEST%d]Failed. Got %v\tExpected %v\n", v.desc, i+1, err, v.err)
 		}
 	}
 }
@@ -404,21 +920,22 @@ func TestBook_Delete(t *testing.T) {
 		{desc: "valid", id: 1, rowAffected: 1, checkBook: false, err: nil},
 		{desc: "invalid id", id: -2, rowAffected: 0, checkBook: false, err: nil},
 		{desc: "error isBookPresent", id: 3, rowAffected: 0, checkBook: true, err: nil},
-		{desc: "error in datastore delete", id: 4, rowAffected: 0, checkBook: false, err: errors.Error("error in delete")},
+		{desc: "error in datastore delete", id: 4, rowAffected: 0, checkBook: false, err: gofrErrors.Error("error in delete")},
 	}
 
 	ctr := gomock.NewController(t)
 	mockBook := datastore.NewMockBook(ctr)
 	mockAuthor := datastore.NewMockAuthor(ctr)
-	service := New(mockBook, mockAuthor)
+	service := New(mockBook, mockAuthor, newMockPublisher(ctr),
+		newMockUnitOfWork(ctr, mockBook, mockAuthor), clock, 0, models.DuplicateISBN)
 
 	for i, v := range testcases {
 		var c *gofr.Context
 
-		mockBook.EXPECT().IsBookPresent(c, v.id).Return(v.checkBook).AnyTimes()
-		mockBook.EXPECT().Delete(c, v.id).Return(v.rowAffected, v.err).AnyTimes()
+		mockBook.EXPECT().Exists(c, v.id).Return(!v.checkBook, nil).AnyTimes()
+		mockBook.EXPECT().Delete(c, v.id, 0).Return(v.rowAffected, v.err).AnyTimes()
 
-		resp, err := service.Delete(c, v.id)
+		resp, err := service.Delete(c, v.id, 0)
 
 		if !reflect.DeepEqual(resp, v.rowAffected) {
 			t.Errorf("desc : %v ,[TEST%d]Failed. Got %v\tExpected %v\n", v.desc, i+1, resp, v.rowAffected)
@@ -429,3 +946,76 @@ func TestBook_Delete(t *testing.T) {
 		}
 	}
 }
+
+// TestBook_Version function is to test that a change conditioned on a version the book is no longer at
+// is rejected as a failed precondition
+func TestBook_Version(t *testing.T) {
+	book := models.Book{AuthorID: 1, Title: "300 Days", Publication: "Penguin",
+		PublishedDate: models.NewDate(2016, 3, 17)}
+	title := "States"
+	changed := errors.PreconditionFailed{Entity: "Book", ID: "1"}
+
+	ctr := gomock.NewController(t)
+	mockBook := datastore.NewMockBook(ctr)
+	mockAuthor := datastore.NewMockAuthor(ctr)
+	service := New(mockBook, mockAuthor, newMockPublisher(ctr),
+		newMockUnitOfWork(ctr, mockBook, mockAuthor), clock, 0, models.DuplicateISBN)
+
+	var c *gofr.Context
+
+	mockBook.EXPECT().Exists(c, 1).Return(true, nil).AnyTimes()
+	mockAuthor.EXPECT().Lock(c, 1).Return(author, nil).AnyTimes()
+	mockBook.EXPECT().GetByID(c, 1).Return(book, nil).AnyTimes()
+	mockBook.EXPECT().GetDuplicate(c, 1, gomock.Any(), false).Return(models.Book{}, sql.ErrNoRows).AnyTimes()
+	mockBook.EXPECT().Update(c, 1, &book, 3).Return(models.Book{}, sql.ErrNoRows)
+	mockBook.EXPECT().Patch(c, 1, models.BookPatch{Title: &title}, 3).Return(models.Book{}, sql.ErrNoRows)
+	mockBook.EXPECT().Delete(c, 1, 3).Return(0, nil)
+
+	if _, err := service.Update(c, 1, &book, 3); !reflect.DeepEqual(err, changed) {
+		t.Errorf("desc : update ,Failed. Got %v\tExpected %v\n", err, changed)
+	}
+
+	if _, err := service.Patch(c, 1, models.BookPatch{Title: &title}, 3); !reflect.DeepEqual(err, changed) {
+		t.Errorf("desc : patch ,Failed. Got %v\tExpected %v\n", err, changed)
+	}
+
+	if _, err := service.Delete(c, 1, 3); !reflect.DeepEqual(err, changed) {
+		t.Errorf("desc : delete ,Failed. Got %v\tExpected %v\n", err, changed)
+	}
+}
+
+// TestBook_DeletedAfterCheck function is to test that a book deleted after it was checked is not found
+func TestBook_DeletedAfterCheck(t *testing.T) {
+	book := models.Book{AuthorID: 1, Title: "300 Days", Publication: "Penguin",
+		PublishedDate: models.NewDate(2016, 3, 17)}
+	title := "States"
+	notFound := errors.NotFound{Entity: "Book", ID: "1"}
+
+	ctr := gomock.NewController(t)
+	mockBook := datastore.NewMockBook(ctr)
+	mockAuthor := datastore.NewMockAuthor(ctr)
+	service := New(mockBook, mockAuthor, newMockPublisher(ctr),
+		newMockUnitOfWork(ctr, mockBook, mockAuthor), clock, 0, models.DuplicateISBN)
+
+	var c *gofr.Context
+
+	mockBook.EXPECT().Exists(c, 1).Return(true, nil).AnyTimes()
+	mockAuthor.EXPECT().Lock(c, 1).Return(author, nil).AnyTimes()
+	mockBook.EXPECT().GetByID(c, 1).Return(book, nil).AnyTimes()
+	mockBook.EXPECT().GetDuplicate(c, 1, gomock.Any(), false).Return(models.Book{}, sql.ErrNoRows).AnyTimes()
+	mockBook.EXPECT().Update(c, 1, &book, 0).Return(models.Book{}, sql.ErrNoRows)
+	mockBook.EXPECT().Patch(c, 1, models.BookPatch{Title: &title}, 0).Return(models.Book{}, sql.ErrNoRows)
+	mockBook.EXPECT().Delete(c, 1, 0).Return(0, nil)
+
+	if _, err := service.Update(c, 1, &book, 0); !reflect.DeepEqual(err, notFound) {
+		t.Errorf("desc : update ,Failed. Got %v\tExpected %v\n", err, notFound)
+	}
+
+	if _, err := service.Patch(c, 1, models.BookPatch{Title: &title}, 0); !reflect.DeepEqual(err, notFound) {
+		t.Errorf("desc : patch ,Failed. Got %v\tExpected %v\n", err, notFound)
+	}
+
+	if _, err := service.Delete(c, 1, 0); !reflect.DeepEqual(err, notFound) {
+		t.Errorf("desc : delete ,Failed. Got %v\tExpected %v\n", err, notFound)
+	}
+}
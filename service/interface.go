@@ -7,14 +7,29 @@ import (
 
 type Book interface {
 	Post(c *gofr.Context, book *models.Book) (models.Book, error)
-	GetAll(c *gofr.Context, title, includeAuthor string) ([]models.Book, error)
-	GetByID(c *gofr.Context, id int) (models.Book, error)
-	Update(c *gofr.Context, id int, book *models.Book) (models.Book, error)
-	Delete(c *gofr.Context, id int) (int, error)
+	GetAll(c *gofr.Context, filter models.BookFilter, includeAuthor string, sort []models.SortField,
+		page models.Page) ([]models.Book, models.PageMeta, error)
+	GetByID(c *gofr.Context, id int, includeAuthor string) (models.Book, error)
+	GetByISBN(c *gofr.Context, isbn, includeAuthor string) (models.Book, error)
+	GetByAuthorID(c *gofr.Context, authorID int, page models.Page) ([]models.Book, models.PageMeta, error)
+	Update(c *gofr.Context, id int, book *models.Book, version int) (models.Book, error)
+	Patch(c *gofr.Context, id int, patch models.BookPatch, version int) (models.Book, error)
+	Delete(c *gofr.Context, id, version int) (int, error)
 }
 
 type Author interface {
 	Post(c *gofr.Context, auth models.Author) (models.Author, error)
-	Update(c *gofr.Context, id int, author models.Author) (models.Author, error)
+	GetAll(c *gofr.Context) ([]models.Author, error)
+	GetByID(c *gofr.Context, id int) (models.Author, error)
+	Update(c *gofr.Context, id int, author models.Author, version int) (models.Author, error)
+	Patch(c *gofr.Context, id int, patch models.AuthorPatch, version int) (models.Author, error)
+	Delete(c *gofr.Context, id int, policy models.DeletePolicy, version int) (int, error)
+}
+
+type Publisher interface {
+	Post(c *gofr.Context, publisher models.Publisher) (models.Publisher, error)
+	GetAll(c *gofr.Context) ([]models.Publisher, error)
+	GetByID(c *gofr.Context, id int) (models.Publisher, error)
+	Update(c *gofr.Context, id int, publisher models.Publisher) (models.Publisher, error)
 	Delete(c *gofr.Context, id int) (int, error)
 }
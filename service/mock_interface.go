@@ -36,48 +36,95 @@ func (m *MockBook) EXPECT() *MockBookMockRecorder {
 }
 
 // Delete mocks base method.
-func (m *MockBook) Delete(c *gofr.Context, id int) (int, error) {
+func (m *MockBook) Delete(c *gofr.Context, id, version int) (int, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Delete", c, id)
+	ret := m.ctrl.Call(m, "Delete", c, id, version)
 	ret0, _ := ret[0].(int)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // Delete indicates an expected call of Delete.
-func (mr *MockBookMockRecorder) Delete(c, id interface{}) *gomock.Call {
+func (mr *MockBookMockRecorder) Delete(c, id, version interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockBook)(nil).Delete), c, id)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockBook)(nil).Delete), c, id, version)
 }
 
 // GetAll mocks base method.
-func (m *MockBook) GetAll(c *gofr.Context, title, includeAuthor string) ([]models.Book, error) {
+func (m *MockBook) GetAll(c *gofr.Context, filter models.BookFilter, includeAuthor string, sort []models.SortField, page models.Page) ([]models.Book, models.PageMeta, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetAll", c, title, includeAuthor)
+	ret := m.ctrl.Call(m, "GetAll", c, filter, includeAuthor, sort, page)
 	ret0, _ := ret[0].([]models.Book)
-	ret1, _ := ret[1].(error)
-	return ret0, ret1
+	ret1, _ := ret[1].(models.PageMeta)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
 }
 
 // GetAll indicates an expected call of GetAll.
-func (mr *MockBookMockRecorder) GetAll(c, title, includeAuthor interface{}) *gomock.Call {
+func (mr *MockBookMockRecorder) GetAll(c, filter, includeAuthor, sort, page interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAll", reflect.TypeOf((*MockBook)(nil).GetAll), c, filter, includeAuthor, sort, page)
+}
+
+// GetByAuthorID mocks base method.
+func (m *MockBook) GetByAuthorID(c *gofr.Context, authorID int, page models.Page) ([]models.Book, models.PageMeta, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByAuthorID", c, authorID, page)
+	ret0, _ := ret[0].([]models.Book)
+	ret1, _ := ret[1].(models.PageMeta)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetByAuthorID indicates an expected call of GetByAuthorID.
+func (mr *MockBookMockRecorder) GetByAuthorID(c, authorID, page interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAll", reflect.TypeOf((*MockBook)(nil).GetAll), c, title, includeAuthor)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByAuthorID", reflect.TypeOf((*MockBook)(nil).GetByAuthorID), c, authorID, page)
 }
 
 // GetByID mocks base method.
-func (m *MockBook) GetByID(c *gofr.Context, id int) (models.Book, error) {
+func (m *MockBook) GetByID(c *gofr.Context, id int, includeAuthor string) (models.Book, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetByID", c, id)
+	ret := m.ctrl.Call(m, "GetByID", c, id, includeAuthor)
 	ret0, _ := ret[0].(models.Book)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetByID indicates an expected call of GetByID.
-func (mr *MockBookMockRecorder) GetByID(c, id interface{}) *gomock.Call {
+func (mr *MockBookMockRecorder) GetByID(c, id, includeAuthor interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByID", reflect.TypeOf((*MockBook)(nil).GetByID), c, id, includeAuthor)
+}
+
+// GetByISBN mocks base method.
+func (m *MockBook) GetByISBN(c *gofr.Context, isbn, includeAuthor string) (models.Book, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByISBN", c, isbn, includeAuthor)
+	ret0, _ := ret[0].(models.Book)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByISBN indicates an expected call of GetByISBN.
+func (mr *MockBookMockRecorder) GetByISBN(c, isbn, includeAuthor interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByISBN", reflect.TypeOf((*MockBook)(nil).GetByISBN), c, isbn, includeAuthor)
+}
+
+// Patch mocks base method.
+func (m *MockBook) Patch(c *gofr.Context, id int, patch models.BookPatch, version int) (models.Book, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Patch", c, id, patch, version)
+	ret0, _ := ret[0].(models.Book)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Patch indicates an expected call of Patch.
+func (mr *MockBookMockRecorder) Patch(c, id, patch, version interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByID", reflect.TypeOf((*MockBook)(nil).GetByID), c, id)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Patch", reflect.TypeOf((*MockBook)(nil).Patch), c, id, patch, version)
 }
 
 // Post mocks base method.
@@ -96,18 +143,18 @@ func (mr *MockBookMockRecorder) Post(c, book interface{}) *gomock.Call {
 }
 
 // Update mocks base method.
-func (m *MockBook) Update(c *gofr.Context, id int, book *models.Book) (models.Book, error) {
+func (m *MockBook) Update(c *gofr.Context, id int, book *models.Book, version int) (models.Book, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Update", c, id, book)
+	ret := m.ctrl.Call(m, "Update", c, id, book, version)
 	ret0, _ := ret[0].(models.Book)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // Update indicates an expected call of Update.
-func (mr *MockBookMockRecorder) Update(c, id, book interface{}) *gomock.Call {
+func (mr *MockBookMockRecorder) Update(c, id, book, version interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockBook)(nil).Update), c, id, book)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockBook)(nil).Update), c, id, book, version)
 }
 
 // MockAuthor is a mock of Author interface.
@@ -134,18 +181,63 @@ func (m *MockAuthor) EXPECT() *MockAuthorMockRecorder {
 }
 
 // Delete mocks base method.
-func (m *MockAuthor) Delete(c *gofr.Context, id int) (int, error) {
+func (m *MockAuthor) Delete(c *gofr.Context, id int, policy models.DeletePolicy, version int) (int, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Delete", c, id)
+	ret := m.ctrl.Call(m, "Delete", c, id, policy, version)
 	ret0, _ := ret[0].(int)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // Delete indicates an expected call of Delete.
-func (mr *MockAuthorMockRecorder) Delete(c, id interface{}) *gomock.Call {
+func (mr *MockAuthorMockRecorder) Delete(c, id, policy, version interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockAuthor)(nil).Delete), c, id)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockAuthor)(nil).Delete), c, id, policy, version)
+}
+
+// GetAll mocks base method.
+func (m *MockAuthor) GetAll(c *gofr.Context) ([]models.Author, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAll", c)
+	ret0, _ := ret[0].([]models.Author)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAll indicates an expected call of GetAll.
+func (mr *MockAuthorMockRecorder) GetAll(c interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAll", reflect.TypeOf((*MockAuthor)(nil).GetAll), c)
+}
+
+// GetByID mocks base method.
+func (m *MockAuthor) GetByID(c *gofr.Context, id int) (models.Author, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByID", c, id)
+	ret0, _ := ret[0].(models.Author)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByID indicates an expected call of GetByID.
+func (mr *MockAuthorMockRecorder) GetByID(c, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByID", reflect.TypeOf((*MockAuthor)(nil).GetByID), c, id)
+}
+
+// Patch mocks base method.
+func (m *MockAuthor) Patch(c *gofr.Context, id int, patch models.AuthorPatch, version int) (models.Author, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Patch", c, id, patch, version)
+	ret0, _ := ret[0].(models.Author)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Patch indicates an expected call of Patch.
+func (mr *MockAuthorMockRecorder) Patch(c, id, patch, version interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Patch", reflect.TypeOf((*MockAuthor)(nil).Patch), c, id, patch, version)
 }
 
 // Post mocks base method.
@@ -164,16 +256,114 @@ func (mr *MockAuthorMockRecorder) Post(c, auth interface{}) *gomock.Call {
 }
 
 // Update mocks base method.
-func (m *MockAuthor) Update(c *gofr.Context, id int, author models.Author) (models.Author, error) {
+func (m *MockAuthor) Update(c *gofr.Context, id int, author models.Author, version int) (models.Author, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Update", c, id, author)
+	ret := m.ctrl.Call(m, "Update", c, id, author, version)
 	ret0, _ := ret[0].(models.Author)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // Update indicates an expected call of Update.
-func (mr *MockAuthorMockRecorder) Update(c, id, author interface{}) *gomock.Call {
+func (mr *MockAuthorMockRecorder) Update(c, id, author, version interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockAuthor)(nil).Update), c, id, author, version)
+}
+
+// MockPublisher is a mock of Publisher interface.
+type MockPublisher struct {
+	ctrl     *gomock.Controller
+	recorder *MockPublisherMockRecorder
+}
+
+// MockPublisherMockRecorder is the mock recorder for MockPublisher.
+type MockPublisherMockRecorder struct {
+	mock *MockPublisher
+}
+
+// NewMockPublisher creates a new mock instance.
+func NewMockPublisher(ctrl *gomock.Controller) *MockPublisher {
+	mock := &MockPublisher{ctrl: ctrl}
+	mock.recorder = &MockPublisherMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockPublisher) EXPECT() *MockPublisherMockRecorder {
+	return m.recorder
+}
+
+// Delete mocks base method.
+func (m *MockPublisher) Delete(c *gofr.Context, id int) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", c, id)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockPublisherMockRecorder) Delete(c, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockPublisher)(nil).Delete), c, id)
+}
+
+// GetAll mocks base method.
+func (m *MockPublisher) GetAll(c *gofr.Context) ([]models.Publisher, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAll", c)
+	ret0, _ := ret[0].([]models.Publisher)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAll indicates an expected call of GetAll.
+func (mr *MockPublisherMockRecorder) GetAll(c interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAll", reflect.TypeOf((*MockPublisher)(nil).GetAll), c)
+}
+
+// GetByID mocks base method.
+func (m *MockPublisher) GetByID(c *gofr.Context, id int) (models.Publisher, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByID", c, id)
+	ret0, _ := ret[0].(models.Publisher)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByID indicates an expected call of GetByID.
+func (mr *MockPublisherMockRecorder) GetByID(c, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByID", reflect.TypeOf((*MockPublisher)(nil).GetByID), c, id)
+}
+
+// Post mocks base method.
+func (m *MockPublisher) Post(c *gofr.Context, publisher models.Publisher) (models.Publisher, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Post", c, publisher)
+	ret0, _ := ret[0].(models.Publisher)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Post indicates an expected call of Post.
+func (mr *MockPublisherMockRecorder) Post(c, publisher interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Post", reflect.TypeOf((*MockPublisher)(nil).Post), c, publisher)
+}
+
+// Update mocks base method.
+func (m *MockPublisher) Update(c *gofr.Context, id int, publisher models.Publisher) (models.Publisher, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Update", c, id, publisher)
+	ret0, _ := ret[0].(models.Publisher)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Update indicates an expected call of Update.
+func (mr *MockPublisherMockRecorder) Update(c, id, publisher interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockAuthor)(nil).Update), c, id, author)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockPublisher)(nil).Update), c, id, publisher)
 }
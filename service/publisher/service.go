@@ -0,0 +1,181 @@
+package publisher
+
+import (
+	"developer.zopsmart.com/go/gofr/pkg/gofr"
+
+	"database/sql"
+	"fmt"
+	"strconv"
+
+	"mytest/datastore"
+	"mytest/models"
+	"mytest/models/errors"
+	"mytest/models/validate"
+)
+
+var errInvalidID = errors.InvalidParam{Code: errors.CodeInvalidParam, Params: []string{"id"}}
+
+type Service struct {
+	datastore datastore.Publisher
+	// unitOfWork is to rename or delete a publisher in the same transaction its books are changed or counted in
+	unitOfWork datastore.UnitOfWork
+}
+
+func New(publisher datastore.Publisher, unitOfWork datastore.UnitOfWork) Service {
+	return Service{datastore: publisher, unitOfWork: unitOfWork}
+}
+
+// Post Publisher details, the name must not be taken by another publisher whatever its case
+func (s Service) Post(c *gofr.Context, publisher models.Publisher) (models.Publisher, error) {
+	if err := validatePublisher(publisher); err != nil {
+		return models.Publisher{}, err
+	}
+
+	if err := checkName(c, s.datastore, 0, publisher.Name); err != nil {
+		return models.Publisher{}, err
+	}
+
+	return s.datastore.Post(c, publisher)
+}
+
+// GetAll Publisher details
+func (s Service) GetAll(c *gofr.Context) ([]models.Publisher, error) {
+	publishers, err := s.datastore.GetAll(c)
+	if err != nil {
+		return []models.Publisher{}, err
+	}
+
+	return publishers, nil
+}
+
+// GetByID Publisher details by its ID
+func (s Service) GetByID(c *gofr.Context, id int) (models.Publisher, error) {
+	if id <= 0 {
+		return models.Publisher{}, errInvalidID
+	}
+
+	return get(c, s.datastore, id)
+}
+
+// Update Publisher details, the books of the publisher are moved to its new name by the foreign key of Book.
+// The versions of the books are bumped and the publisher renamed in one transaction, as a rename changes
+// the books.
+func (s Service) Update(c *gofr.Context, id int, publisher models.Publisher) (models.Publisher, error) {
+	if id <= 0 {
+		return models.Publisher{}, errInvalidID
+	}
+
+	if err := validatePublisher(publisher); err != nil {
+		return models.Publisher{}, err
+	}
+
+	var updated models.Publisher
+
+	err := s.unitOfWork.Do(c, func(tx datastore.Tx) error {
+		stored, err := get(c, tx.Publisher(), id)
+		if err != nil {
+			return err
+		}
+
+		if err := checkName(c, tx.Publisher(), id, publisher.Name); err != nil {
+			return err
+		}
+
+		if stored.Name != publisher.Name {
+			if _, err := tx.Book().TouchPublication(c, stored.Name); err != nil {
+				return err
+			}
+		}
+
+		updated, err = tx.Publisher().Update(c, id, publisher)
+
+		return err
+	})
+	if err != nil {
+		return models.Publisher{}, err
+	}
+
+	return updated, nil
+}
+
+// Delete Publisher by its ID, a publisher with books cannot be deleted. The publisher is locked, its books
+// counted and the publisher deleted in one transaction, so that no book is added to it in between.
+func (s Service) Delete(c *gofr.Context, id int) (int, error) {
+	if id <= 0 {
+		return 0, errInvalidID
+	}
+
+	var rowAffected int
+
+	err := s.unitOfWork.Do(c, func(tx datastore.Tx) error {
+		publisher, err := tx.Publisher().LockForUpdate(c, id)
+		if err != nil {
+			return notFound(err, id)
+		}
+
+		count, err := tx.Book().Count(c, models.BookFilter{Publication: publisher.Name})
+		if err != nil {
+			return err
+		}
+
+		if count > 0 {
+			return errors.Conflict{Code: errors.CodePublisherHasBooks,
+				Reason: fmt.Sprintf("publisher %v has %v books", id, count), ResourceID: strconv.Itoa(id)}
+		}
+
+		rowAffected, err = tx.Publisher().Delete(c, id)
+
+		return err
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return rowAffected, nil
+}
+
+// get is to read the publisher, NotFound is returned when it does not exist
+func get(c *gofr.Context, publishers datastore.Publisher, id int) (models.Publisher, error) {
+	publisher, err := publishers.GetByID(c, id)
+	if err != nil {
+		return models.Publisher{}, notFound(err, id)
+	}
+
+	return publisher, nil
+}
+
+// notFound is to tell the sql.ErrNoRows of reading the publisher from any other failure
+func notFound(err error, id int) error {
+	if err == sql.ErrNoRows {
+		return errors.NotFound{Entity: "Publisher", ID: strconv.Itoa(id)}
+	}
+
+	return err
+}
+
+// checkName is to make sure that the name is not taken by a publisher other than the one with the given id
+func checkName(c *gofr.Context, publishers datastore.Publisher, id int, name string) error {
+	publisher, err := publishers.GetByName(c, name)
+
+	switch {
+	case err == sql.ErrNoRows:
+		return nil
+	case err != nil:
+		return err
+	case publisher.PublisherID != id:
+		return errors.Conflict{Code: errors.CodePublisherExists,
+			Reason:     fmt.Sprintf("publisher %v already exists", publisher.Name),
+			ResourceID: strconv.Itoa(publisher.PublisherID)}
+	}
+
+	return nil
+}
+
+// validatePublisher is to check the fields of a Publisher, every offending field is reported
+func validatePublisher(publisher models.Publisher) error {
+	if fields := validate.Struct(publisher, nil); len(fields) > 0 {
+		return errors.Validation{Code: errors.CodeInvalidField, Fields: fields, Reason: "invalid publisher"}
+	}
+
+	return nil
+}
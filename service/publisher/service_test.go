@@ -0,0 +1,266 @@
+package publisher
+
+import (
+	"database/sql"
+	gofrErrors "developer.zopsmart.com/go/gofr/pkg/errors"
+	"developer.zopsmart.com/go/gofr/pkg/gofr"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+
+	"mytest/datastore"
+	"mytest/models"
+	"mytest/models/errors"
+)
+
+// newMockUnitOfWork is to get a unit of work running the work on the given datastores
+func newMockUnitOfWork(ctr *gomock.Controller, book datastore.Book,
+	publisher datastore.Publisher) *datastore.MockUnitOfWork {
+	tx := datastore.NewMockTx(ctr)
+	tx.EXPECT().Book().Return(book).AnyTimes()
+	tx.EXPECT().Publisher().Return(publisher).AnyTimes()
+
+	unitOfWork := datastore.NewMockUnitOfWork(ctr)
+	unitOfWork.EXPECT().Do(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(c *gofr.Context, work func(tx datastore.Tx) error) error {
+			return work(tx)
+		}).AnyTimes()
+
+	return unitOfWork
+}
+
+// TestPublisher_Post function is to test onboarding a publisher
+func TestPublisher_Post(t *testing.T) {
+	penguin := models.Publisher{PublisherID: 1, Name: "Penguin"}
+
+	testcases := []struct {
+		desc    string
+		req     models.Publisher
+		byName  models.Publisher
+		nameErr error
+		resp    models.Publisher
+		postErr error
+		err     error
+	}{
+		{desc: "valid", req: models.Publisher{Name: "Penguin"}, nameErr: sql.ErrNoRows, resp: penguin},
+		{desc: "missing name", req: models.Publisher{}, err: errors.Validation{Code: errors.CodeInvalidField,
+			Fields: []errors.FieldError{{Field: "name", Reason: errors.ReasonMissing}}, Reason: "invalid publisher"}},
+		{desc: "name too long", req: models.Publisher{Name: strings.Repeat("a", 51)}, err: errors.Validation{
+			Code: errors.CodeInvalidField, Fields: []errors.FieldError{{Field: "name", Reason: errors.ReasonTooLong}},
+			Reason: "invalid publisher"}},
+		{desc: "name taken in another case", req: models.Publisher{Name: "PENGUIN"}, byName: penguin,
+			err: errors.Conflict{Code: errors.CodePublisherExists, Reason: "publisher Penguin already exists",
+				ResourceID: "1"}},
+		{desc: "error in GetByName", req: models.Publisher{Name: "Penguin"},
+			nameErr: gofrErrors.Error("error in GetByName"), err: gofrErrors.Error("error in GetByName")},
+		{desc: "error in post", req: models.Publisher{Name: "Penguin"}, nameErr: sql.ErrNoRows,
+			postErr: gofrErrors.Error("error in post"), err: gofrErrors.Error("error in post")},
+	}
+
+	for i, v := range testcases {
+		var c *gofr.Context
+
+		ctr := gomock.NewController(t)
+		mockPublisher := datastore.NewMockPublisher(ctr)
+		service := New(mockPublisher, datastore.NewMockUnitOfWork(ctr))
+
+		mockPublisher.EXPECT().GetByName(c, v.req.Name).Return(v.byName, v.nameErr).MaxTimes(1)
+		mockPublisher.EXPECT().Post(c, v.req).Return(v.resp, v.postErr).MaxTimes(1)
+
+		resp, err := service.Post(c, v.req)
+
+		if !reflect.DeepEqual(resp, v.resp) {
+			t.Errorf("desc : %v ,[TEST%d]Failed. Got %v\tExpected %v\n", v.desc, i+1, resp, v.resp)
+		}
+
+		if !reflect.DeepEqual(err, v.err) {
+			t.Errorf("desc : %v ,[TEST%d]Failed. Got %v\tExpected %v\n", v.desc, i+1, err, v.err)
+		}
+	}
+}
+
+// TestPublisher_GetAll function is to test listing the publishers
+func TestPublisher_GetAll(t *testing.T) {
+	testcases := []struct {
+		desc string
+		resp []models.Publisher
+		err  error
+	}{
+		{desc: "valid", resp: []models.Publisher{{PublisherID: 1, Name: "Penguin"}}},
+		{desc: "error in get all", resp: []models.Publisher{}, err: gofrErrors.Error("error in get all")},
+	}
+
+	for i, v := range testcases {
+		var c *gofr.Context
+
+		ctr := gomock.NewController(t)
+		mockPublisher := datastore.NewMockPublisher(ctr)
+		service := New(mockPublisher, datastore.NewMockUnitOfWork(ctr))
+
+		mockPublisher.EXPECT().GetAll(c).Return(v.resp, v.err)
+
+		resp, err := service.GetAll(c)
+
+		if !reflect.DeepEqual(resp, v.resp) {
+			t.Errorf("desc : %v ,[TEST%d]Failed. Got %v\tExpected %v\n", v.desc, i+1, resp, v.resp)
+		}
+
+		if !reflect.DeepEqual(err, v.err) {
+			t.Errorf("desc : %v ,[TEST%d]Failed. Got %v\tExpected %v\n", v.desc, i+1, err, v.err)
+		}
+	}
+}
+
+// TestPublisher_GetByID function is to test getting a publisher by its id
+func TestPublisher_GetByID(t *testing.T) {
+	testcases := []struct {
+		desc   string
+		id     int
+		stored models.Publisher
+		getErr error
+		resp   models.Publisher
+		err    error
+	}{
+		{desc: "valid", id: 1, stored: models.Publisher{PublisherID: 1, Name: "Penguin"},
+			resp: models.Publisher{PublisherID: 1, Name: "Penguin"}},
+		{desc: "invalid id", id: 0, err: errInvalidID},
+		{desc: "not found", id: 2, getErr: sql.ErrNoRows, err: errors.NotFound{Entity: "Publisher", ID: "2"}},
+		{desc: "error in get", id: 3, getErr: gofrErrors.Error("error in get"), err: gofrErrors.Error("error in get")},
+	}
+
+	for i, v := range testcases {
+		var c *gofr.Context
+
+		ctr := gomock.NewController(t)
+		mockPublisher := datastore.NewMockPublisher(ctr)
+		service := New(mockPublisher, datastore.NewMockUnitOfWork(ctr))
+
+		mockPublisher.EXPECT().GetByID(c, v.id).Return(v.stored, v.getErr).MaxTimes(1)
+
+		resp, err := service.GetByID(c, v.id)
+
+		if !reflect.DeepEqual(resp, v.resp) {
+			t.Errorf("desc : %v ,[TEST%d]Failed. Got %v\tExpected %v\n", v.desc, i+1, resp, v.resp)
+		}
+
+		if !reflect.DeepEqual(err, v.err) {
+			t.Errorf("desc : %v ,[TEST%d]Failed. Got %v\tExpected %v\n", v.desc, i+1, err, v.err)
+		}
+	}
+}
+
+// TestPublisher_Update function is to test renaming a publisher, which bumps the versions of its books
+func TestPublisher_Update(t *testing.T) {
+	penguin := models.Publisher{PublisherID: 1, Name: "Penguin"}
+
+	testcases := []struct {
+		desc      string
+		id        int
+		req       models.Publisher
+		getErr    error
+		byName    models.Publisher
+		nameErr   error
+		updated   models.Publisher
+		updateErr error
+		rename    bool
+		renameErr error
+		resp      models.Publisher
+		err       error
+	}{
+		{desc: "rename", id: 1, req: models.Publisher{Name: "Penguin Books"}, nameErr: sql.ErrNoRows,
+			updated: models.Publisher{PublisherID: 1, Name: "Penguin Books"}, rename: true,
+			resp: models.Publisher{PublisherID: 1, Name: "Penguin Books"}},
+		{desc: "same name", id: 1, req: models.Publisher{Name: "Penguin"}, byName: penguin, updated: penguin,
+			resp: penguin},
+		{desc: "invalid id", id: -1, err: errInvalidID},
+		{desc: "missing name", id: 1, err: errors.Validation{Code: errors.CodeInvalidField,
+			Fields: []errors.FieldError{{Field: "name", Reason: errors.ReasonMissing}}, Reason: "invalid publisher"}},
+		{desc: "not found", id: 2, req: models.Publisher{Name: "Arihant"}, getErr: sql.ErrNoRows,
+			err: errors.NotFound{Entity: "Publisher", ID: "2"}},
+		{desc: "name taken", id: 3, req: models.Publisher{Name: "penguin"}, byName: penguin,
+			err: errors.Conflict{Code: errors.CodePublisherExists, Reason: "publisher Penguin already exists",
+				ResourceID: "1"}},
+		{desc: "error in update", id: 1, req: models.Publisher{Name: "Penguin Books"}, nameErr: sql.ErrNoRows,
+			rename: true, updateErr: gofrErrors.Error("error in update"), err: gofrErrors.Error("error in update")},
+		{desc: "error in bumping versions", id: 1, req: models.Publisher{Name: "Penguin Books"}, nameErr: sql.ErrNoRows,
+			rename: true, renameErr: gofrErrors.Error("error in touch"), err: gofrErrors.Error("error in touch")},
+	}
+
+	for i, v := range testcases {
+		var c *gofr.Context
+
+		ctr := gomock.NewController(t)
+		mockPublisher := datastore.NewMockPublisher(ctr)
+		mockBook := datastore.NewMockBook(ctr)
+		service := New(mockPublisher, newMockUnitOfWork(ctr, mockBook, mockPublisher))
+
+		mockPublisher.EXPECT().GetByID(c, v.id).Return(penguin, v.getErr).MaxTimes(1)
+		mockPublisher.EXPECT().GetByName(c, v.req.Name).Return(v.byName, v.nameErr).MaxTimes(1)
+		mockPublisher.EXPECT().Update(c, v.id, v.req).Return(v.updated, v.updateErr).MaxTimes(1)
+
+		if v.rename {
+			mockBook.EXPECT().TouchPublication(c, penguin.Name).Return(2, v.renameErr)
+		}
+
+		resp, err := service.Update(c, v.id, v.req)
+
+		if !reflect.DeepEqual(resp, v.resp) {
+			t.Errorf("desc : %v ,[TEST%d]Failed. Got %v\tExpected %v\n", v.desc, i+1, resp, v.resp)
+		}
+
+		if !reflect.DeepEqual(err, v.err) {
+			t.Errorf("desc : %v ,[TEST%d]Failed. Got %v\tExpected %v\n", v.desc, i+1, err, v.err)
+		}
+	}
+}
+
+// TestPublisher_Delete function is to test deleting a publisher which has no books
+func TestPublisher_Delete(t *testing.T) {
+	testcases := []struct {
+		desc      string
+		id        int
+		getErr    error
+		count     int
+		countErr  error
+		deleteErr error
+		resp      int
+		err       error
+	}{
+		{desc: "valid", id: 1, resp: 1},
+		{desc: "invalid id", id: 0, err: errInvalidID},
+		{desc: "not found", id: 2, getErr: sql.ErrNoRows, err: errors.NotFound{Entity: "Publisher", ID: "2"}},
+		{desc: "publisher has books", id: 3, count: 4, err: errors.Conflict{Code: errors.CodePublisherHasBooks,
+			Reason: "publisher 3 has 4 books", ResourceID: "3"}},
+		{desc: "error in count", id: 4, countErr: gofrErrors.Error("error in count"),
+			err: gofrErrors.Error("error in count")},
+		{desc: "error in delete", id: 5, deleteErr: gofrErrors.Error("error in delete"),
+			err: gofrErrors.Error("error in delete")},
+		{desc: "error in lock", id: 6, getErr: gofrErrors.Error("error in lock"), err: gofrErrors.Error("error in lock")},
+	}
+
+	for i, v := range testcases {
+		var c *gofr.Context
+
+		ctr := gomock.NewController(t)
+		mockPublisher := datastore.NewMockPublisher(ctr)
+		mockBook := datastore.NewMockBook(ctr)
+		service := New(mockPublisher, newMockUnitOfWork(ctr, mockBook, mockPublisher))
+
+		mockPublisher.EXPECT().LockForUpdate(c, v.id).Return(models.Publisher{PublisherID: v.id, Name: "Penguin"},
+			v.getErr).MaxTimes(1)
+		mockBook.EXPECT().Count(c, models.BookFilter{Publication: "Penguin"}).Return(v.count, v.countErr).MaxTimes(1)
+		mockPublisher.EXPECT().Delete(c, v.id).Return(v.resp, v.deleteErr).MaxTimes(1)
+
+		resp, err := service.Delete(c, v.id)
+
+		if !reflect.DeepEqual(resp, v.resp) {
+			t.Errorf("desc : %v ,[TEST%d]Failed. Got %v\tExpected %v\n", v.desc, i+1, resp, v.resp)
+		}
+
+		if !reflect.DeepEqual(err, v.err) {
+			t.Errorf("desc : %v ,[TEST%d]Failed. Got %v\tExpected %v\n", v.desc, i+1, err, v.err)
+		}
+	}
+}